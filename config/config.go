@@ -0,0 +1,427 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/content/homePage"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// Sparkline frequencies that may be configured.
+var sparklineFrequencies = []string{"", homePage.FrequencyYears, homePage.FrequencyQuarters, homePage.FrequencyMonths}
+
+// Config holds every tunable of the content resolver.
+type Config struct {
+	BindAddr            string
+	DrainDelay          time.Duration
+	ShutdownTimeout     time.Duration
+	ZebedeeURL          string
+	ZebedeeTimeout      time.Duration
+	ZebedeeCacheTTL     time.Duration
+	ZebedeeContentDir   string
+	ZebedeeRecordDir    string
+	ZebedeeReplayDir    string
+	ReadinessURI        string
+	ReadinessInterval   time.Duration
+	ReadinessThreshold  int
+	TaxonomyDepth       int
+	RequestIDLength     int
+	SiteDomain          string
+	MetadataTitle       string
+	MetadataDescription string
+	MetadataKeywords    []string
+	SparklineFrequency  string
+	SparklinePeriods    int
+	SparklineYears      int
+	SparklineMaxPoints  int
+	SparklineSVG        bool
+	OTLPEndpoint        string
+	OTLPExportInterval  time.Duration
+	DebugTimeline       bool
+	MandatoryComponents []string
+	CacheControl        string
+	BatchConcurrency    int
+	BatchMaxURIs        int
+	ExportConcurrency   int
+	ExportMaxPages      int
+	FeedMaxItems        int
+	FeedTaxonomyDepth   int
+	FeedMaxLinks        int
+	FeedCacheTTL        time.Duration
+	SitemapInterval     time.Duration
+	SitemapMaxURLs      int
+	SitemapWelshDomain  string
+	DiffZebedeeURL      string
+	DiffContentDir      string
+	DiffReplayDir       string
+	DiffIgnore          []string
+
+	// Args holds the arguments remaining after the flags.
+	Args []string
+}
+
+// Default returns the configuration used when no environment variables or flags are set.
+func Default() *Config {
+	return &Config{
+		BindAddr:            ":20020",
+		DrainDelay:          time.Second * 15,
+		ShutdownTimeout:     time.Second * 10,
+		ZebedeeURL:          "http://localhost:8082",
+		ZebedeeTimeout:      time.Second * 2,
+		ReadinessURI:        "/",
+		ReadinessInterval:   time.Second * 10,
+		ReadinessThreshold:  3,
+		TaxonomyDepth:       2,
+		RequestIDLength:     16,
+		SiteDomain:          "https://www.ons.gov.uk",
+		MetadataTitle:       "Office for National Statistics",
+		MetadataDescription: "The UK's largest independent producer of official statistics and its recognised national statistical institute.",
+		MetadataKeywords:    []string{"statistics", "economy", "census", "population", "inflation", "employment"},
+		OTLPExportInterval:  time.Second * 5,
+		BatchConcurrency:    8,
+		BatchMaxURIs:        100,
+		ExportConcurrency:   4,
+		ExportMaxPages:      10000,
+		FeedMaxItems:        20,
+		FeedTaxonomyDepth:   2,
+		FeedMaxLinks:        200,
+		FeedCacheTTL:        time.Minute * 5,
+		SitemapInterval:     time.Hour,
+		SitemapMaxURLs:      50000,
+		SitemapWelshDomain:  "https://cy.ons.gov.uk",
+	}
+}
+
+// Load returns the configuration built from the defaults, then environment variables, then the command line
+// arguments. Each flag may also be set by an environment variable of the same name in upper snake case, e.g.
+// -zebedee-url and ZEBEDEE_URL. An error is returned if any value cannot be parsed or is invalid.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+	flags := cfg.flagSet()
+
+	var envErr error
+	flags.VisitAll(func(f *flag.Flag) {
+		name := EnvName(f.Name)
+		if value := os.Getenv(name); len(value) > 0 && envErr == nil {
+			if err := f.Value.Set(value); err != nil {
+				envErr = fmt.Errorf("invalid value %q for %s: %s", value, name, err)
+			}
+		}
+	})
+	if envErr != nil {
+		return nil, envErr
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		cfg.Args = flags.Args()
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// EnvName returns the environment variable name for the flag name.
+func EnvName(flagName string) string {
+	return strings.ToUpper(strings.Replace(flagName, "-", "_", -1))
+}
+
+func (cfg *Config) flagSet() *flag.FlagSet {
+	flags := flag.NewFlagSet("dp-content-resolver", flag.ContinueOnError)
+	flags.StringVar(&cfg.BindAddr, "bind-addr", cfg.BindAddr, "The host and port to bind to.")
+	flags.DurationVar(&cfg.DrainDelay, "drain-delay", cfg.DrainDelay, "How long to keep serving after reporting not ready on SIGTERM/SIGINT, so that Consul takes the service out of rotation first.")
+	flags.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", cfg.ShutdownTimeout, "How long to wait for in-flight requests to complete when shutting down.")
+	flags.StringVar(&cfg.ZebedeeURL, "zebedee-url", cfg.ZebedeeURL, "The Zebedee instance URL to use when resolving.")
+	flags.DurationVar(&cfg.ZebedeeTimeout, "zebedee-timeout", cfg.ZebedeeTimeout, "The timeout of each request to Zebedee.")
+	flags.DurationVar(&cfg.ZebedeeCacheTTL, "zebedee-cache-ttl", cfg.ZebedeeCacheTTL, "How long to cache Zebedee responses. 0 disables caching.")
+	flags.StringVar(&cfg.ZebedeeRecordDir, "zebedee-record-dir", cfg.ZebedeeRecordDir, "Record every Zebedee request and response to fixtures in this directory. Empty disables recording.")
+	flags.StringVar(&cfg.ZebedeeReplayDir, "zebedee-replay-dir", cfg.ZebedeeReplayDir, "Replay the Zebedee fixtures recorded in this directory instead of calling Zebedee. Empty calls Zebedee.")
+	flags.StringVar(&cfg.ZebedeeContentDir, "zebedee-content-dir", cfg.ZebedeeContentDir, "Read content from this Zebedee content directory instead of calling Zebedee. Empty calls Zebedee.")
+	flags.StringVar(&cfg.ReadinessURI, "readiness-uri", cfg.ReadinessURI, "The URI requested from Zebedee to check readiness.")
+	flags.DurationVar(&cfg.ReadinessInterval, "readiness-interval", cfg.ReadinessInterval, "How often to check Zebedee for readiness.")
+	flags.IntVar(&cfg.ReadinessThreshold, "readiness-threshold", cfg.ReadinessThreshold, "Consecutive Zebedee check failures before the service is not ready.")
+	flags.IntVar(&cfg.TaxonomyDepth, "taxonomy-depth", cfg.TaxonomyDepth, "The depth of the taxonomy resolved for each page.")
+	flags.IntVar(&cfg.RequestIDLength, "request-id-length", cfg.RequestIDLength, "The length of generated X-Request-Id headers.")
+	flags.StringVar(&cfg.SiteDomain, "site-domain", cfg.SiteDomain, "The domain used to build canonical URIs in page metadata.")
+	flags.StringVar(&cfg.MetadataTitle, "metadata-title", cfg.MetadataTitle, "The page title used when Zebedee does not provide one.")
+	flags.StringVar(&cfg.MetadataDescription, "metadata-description", cfg.MetadataDescription, "The page description used when Zebedee does not provide one.")
+	flags.Var((*listValue)(&cfg.MetadataKeywords), "metadata-keywords", "Comma separated keywords used when Zebedee does not provide any.")
+	flags.StringVar(&cfg.SparklineFrequency, "sparkline-frequency", cfg.SparklineFrequency, "Headline sparkline frequency: years, quarters or months. Empty uses the Zebedee series.")
+	flags.IntVar(&cfg.SparklinePeriods, "sparkline-periods", cfg.SparklinePeriods, "Limit headline sparklines to the last N periods. 0 includes all.")
+	flags.IntVar(&cfg.SparklineYears, "sparkline-years", cfg.SparklineYears, "Limit headline sparklines to the last N years. 0 includes all.")
+	flags.IntVar(&cfg.SparklineMaxPoints, "sparkline-max-points", cfg.SparklineMaxPoints, "Downsample headline sparklines to at most N points. 0 disables downsampling.")
+	flags.BoolVar(&cfg.SparklineSVG, "sparkline-svg", cfg.SparklineSVG, "Embed each headline sparkline rendered as SVG in the resolved homepage.")
+	flags.StringVar(&cfg.OTLPEndpoint, "otlp-endpoint", cfg.OTLPEndpoint, "The OpenTelemetry collector OTLP/HTTP URL to export traces to, e.g. http://localhost:4318. Empty disables export.")
+	flags.DurationVar(&cfg.OTLPExportInterval, "otlp-export-interval", cfg.OTLPExportInterval, "How often to export traces to the collector.")
+	flags.Var((*listValue)(&cfg.MandatoryComponents), "mandatory-components", "Comma separated page type:component pairs that fail the whole resolve if they fail, e.g. home_page:taxonomy.")
+	flags.StringVar(&cfg.CacheControl, "cache-control", cfg.CacheControl, "Semicolon separated page type:Cache-Control header pairs for resolved pages, with * for other page types, e.g. home_page:public, max-age=60;*:no-cache.")
+	flags.IntVar(&cfg.BatchConcurrency, "batch-concurrency", cfg.BatchConcurrency, "The maximum number of pages of a batch resolved at the same time.")
+	flags.IntVar(&cfg.BatchMaxURIs, "batch-max-uris", cfg.BatchMaxURIs, "The maximum number of pages that may be resolved in a single batch.")
+	flags.IntVar(&cfg.ExportConcurrency, "export-concurrency", cfg.ExportConcurrency, "The maximum number of pages resolved at the same time by the export command.")
+	flags.IntVar(&cfg.ExportMaxPages, "export-max-pages", cfg.ExportMaxPages, "The maximum number of pages crawled by the export command.")
+	flags.IntVar(&cfg.FeedMaxItems, "feed-max-items", cfg.FeedMaxItems, "The maximum number of publications listed in each RSS and Atom feed.")
+	flags.IntVar(&cfg.FeedTaxonomyDepth, "feed-taxonomy-depth", cfg.FeedTaxonomyDepth, "The depth of the taxonomy beneath a feed page searched for publications.")
+	flags.IntVar(&cfg.FeedMaxLinks, "feed-max-links", cfg.FeedMaxLinks, "The maximum number of linked pages read to find the publications of each feed.")
+	flags.DurationVar(&cfg.FeedCacheTTL, "feed-cache-ttl", cfg.FeedCacheTTL, "How long to reuse each RSS and Atom feed before finding its publications again. 0 disables caching.")
+	flags.DurationVar(&cfg.SitemapInterval, "sitemap-interval", cfg.SitemapInterval, "How often the sitemap is regenerated.")
+	flags.IntVar(&cfg.SitemapMaxURLs, "sitemap-max-urls", cfg.SitemapMaxURLs, "The maximum number of URLs in each sitemap before it is split and listed by a sitemap index.")
+	flags.StringVar(&cfg.SitemapWelshDomain, "sitemap-welsh-domain", cfg.SitemapWelshDomain, "The domain of the Welsh alternate of each page in the sitemap. Empty omits alternates.")
+	flags.StringVar(&cfg.DiffZebedeeURL, "diff-zebedee-url", cfg.DiffZebedeeURL, "The Zebedee instance URL the diff command compares against.")
+	flags.StringVar(&cfg.DiffContentDir, "diff-content-dir", cfg.DiffContentDir, "The Zebedee content directory the diff command compares against.")
+	flags.StringVar(&cfg.DiffReplayDir, "diff-replay-dir", cfg.DiffReplayDir, "The recorded Zebedee fixtures the diff command compares against.")
+	flags.Var((*listValue)(&cfg.DiffIgnore), "diff-ignore", "Comma separated paths of volatile fields ignored by the diff command, e.g. metadata.releaseDate. * matches any key.")
+	flags.BoolVar(&cfg.DebugTimeline, "debug-timeline", cfg.DebugTimeline, "Allow requests to include a timeline of the Zebedee calls made resolving them.")
+	return flags
+}
+
+// Validate checks every configured value is usable.
+func (cfg *Config) Validate() error {
+	if len(cfg.BindAddr) == 0 {
+		return errors.New("bind address must be set")
+	}
+	if err := validateURL("zebedee url", cfg.ZebedeeURL); err != nil {
+		return err
+	}
+	if err := validateURL("site domain", cfg.SiteDomain); err != nil {
+		return err
+	}
+	if cfg.ZebedeeTimeout <= 0 {
+		return errors.New("zebedee timeout must be a positive duration")
+	}
+	if cfg.DrainDelay < 0 {
+		return errors.New("drain delay must not be negative")
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		return errors.New("shutdown timeout must be a positive duration")
+	}
+	if cfg.ZebedeeCacheTTL < 0 {
+		return errors.New("zebedee cache ttl must not be negative")
+	}
+	for name, dir := range map[string]string{"content": cfg.ZebedeeContentDir, "record": cfg.ZebedeeRecordDir, "replay": cfg.ZebedeeReplayDir,
+		"diff content": cfg.DiffContentDir, "diff replay": cfg.DiffReplayDir} {
+		if len(dir) > 0 {
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				return fmt.Errorf("zebedee %s dir %q must be a directory", name, dir)
+			}
+		}
+	}
+	if len(cfg.ZebedeeContentDir) > 0 && len(cfg.ZebedeeReplayDir) > 0 {
+		return errors.New("only one of zebedee content dir and zebedee replay dir may be set")
+	}
+	if cfg.FeedMaxItems <= 0 || cfg.FeedTaxonomyDepth <= 0 || cfg.FeedMaxLinks <= 0 {
+		return errors.New("feed max items, taxonomy depth and max links must be positive")
+	}
+	if cfg.FeedCacheTTL < 0 {
+		return errors.New("feed cache ttl must not be negative")
+	}
+	if cfg.SitemapInterval <= 0 {
+		return errors.New("sitemap interval must be a positive duration")
+	}
+	if cfg.SitemapMaxURLs <= 0 || cfg.SitemapMaxURLs > 50000 {
+		return errors.New("sitemap max urls must be between 1 and 50000")
+	}
+	if len(cfg.SitemapWelshDomain) > 0 {
+		if err := validateURL("sitemap welsh domain", cfg.SitemapWelshDomain); err != nil {
+			return err
+		}
+	}
+	if len(cfg.DiffZebedeeURL) > 0 {
+		if err := validateURL("diff zebedee url", cfg.DiffZebedeeURL); err != nil {
+			return err
+		}
+	}
+	if len(cfg.DiffContentDir) > 0 && len(cfg.DiffReplayDir) > 0 {
+		return errors.New("only one of diff content dir and diff replay dir may be set")
+	}
+	if len(cfg.ReadinessURI) == 0 {
+		return errors.New("readiness uri must be set")
+	}
+	if cfg.ReadinessInterval <= 0 {
+		return errors.New("readiness interval must be a positive duration")
+	}
+	if cfg.ReadinessThreshold <= 0 {
+		return errors.New("readiness threshold must be positive")
+	}
+	if len(cfg.OTLPEndpoint) > 0 {
+		if err := validateURL("otlp endpoint", cfg.OTLPEndpoint); err != nil {
+			return err
+		}
+	}
+	if cfg.OTLPExportInterval <= 0 {
+		return errors.New("otlp export interval must be a positive duration")
+	}
+	if cfg.TaxonomyDepth <= 0 {
+		return errors.New("taxonomy depth must be positive")
+	}
+	if cfg.RequestIDLength <= 0 {
+		return errors.New("request ID length must be positive")
+	}
+	if cfg.BatchConcurrency <= 0 || cfg.BatchMaxURIs <= 0 {
+		return errors.New("batch concurrency and max uris must be positive")
+	}
+	if cfg.ExportConcurrency <= 0 || cfg.ExportMaxPages <= 0 {
+		return errors.New("export concurrency and max pages must be positive")
+	}
+	if _, err := cfg.MandatoryComponentsByPageType(); err != nil {
+		return err
+	}
+	if _, err := cfg.CacheControlByPageType(); err != nil {
+		return err
+	}
+	if cfg.SparklinePeriods < 0 || cfg.SparklineYears < 0 || cfg.SparklineMaxPoints < 0 {
+		return errors.New("sparkline periods, years and max points must not be negative")
+	}
+
+	for _, frequency := range sparklineFrequencies {
+		if cfg.SparklineFrequency == frequency {
+			return nil
+		}
+	}
+	return fmt.Errorf("sparkline frequency must be one of years, quarters or months, found %q", cfg.SparklineFrequency)
+}
+
+// DiffEnvironment returns the configuration of the Zebedee environment the diff command compares against, or nil if
+// none is configured. It is the same configuration with the Zebedee URL, content dir and replay dir replaced by their diff
+// counterparts, and recording disabled.
+func (cfg *Config) DiffEnvironment() *Config {
+	if len(cfg.DiffZebedeeURL) == 0 && len(cfg.DiffContentDir) == 0 && len(cfg.DiffReplayDir) == 0 {
+		return nil
+	}
+
+	diffCfg := *cfg
+	if len(cfg.DiffZebedeeURL) > 0 {
+		diffCfg.ZebedeeURL = cfg.DiffZebedeeURL
+	}
+	diffCfg.ZebedeeContentDir = cfg.DiffContentDir
+	diffCfg.ZebedeeReplayDir = cfg.DiffReplayDir
+	diffCfg.ZebedeeRecordDir = ""
+	return &diffCfg
+}
+
+// MandatoryComponentsByPageType returns the mandatory components keyed by page type. An error is returned if any are not
+// of the form page type:component.
+func (cfg *Config) MandatoryComponentsByPageType() (map[string][]string, error) {
+	components := make(map[string][]string)
+	for _, value := range cfg.MandatoryComponents {
+		parts := strings.SplitN(value, ":", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return nil, fmt.Errorf("mandatory components must be of the form page type:component, found %q", value)
+		}
+		components[parts[0]] = append(components[parts[0]], parts[1])
+	}
+	return components, nil
+}
+
+// CacheControlByPageType returns the Cache-Control header of resolved pages keyed by page type, with the default keyed
+// by *. An error is returned if any are not of the form page type:header.
+func (cfg *Config) CacheControlByPageType() (map[string]string, error) {
+	cacheControl := make(map[string]string)
+	if len(strings.TrimSpace(cfg.CacheControl)) == 0 {
+		return cacheControl, nil
+	}
+	for _, value := range strings.Split(cfg.CacheControl, ";") {
+		parts := strings.SplitN(value, ":", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 || len(strings.TrimSpace(parts[1])) == 0 {
+			return nil, fmt.Errorf("cache control must be of the form page type:header, found %q", value)
+		}
+		cacheControl[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return cacheControl, nil
+}
+
+// LogData returns the effective configuration for logging, with any credentials redacted.
+func (cfg *Config) LogData() log.Data {
+	return log.Data{
+		"bind_addr":            cfg.BindAddr,
+		"drain_delay":          cfg.DrainDelay.String(),
+		"shutdown_timeout":     cfg.ShutdownTimeout.String(),
+		"zebedee_url":          redactURL(cfg.ZebedeeURL),
+		"zebedee_timeout":      cfg.ZebedeeTimeout.String(),
+		"zebedee_cache_ttl":    cfg.ZebedeeCacheTTL.String(),
+		"zebedee_content_dir":  cfg.ZebedeeContentDir,
+		"zebedee_record_dir":   cfg.ZebedeeRecordDir,
+		"zebedee_replay_dir":   cfg.ZebedeeReplayDir,
+		"readiness_uri":        cfg.ReadinessURI,
+		"readiness_interval":   cfg.ReadinessInterval.String(),
+		"readiness_threshold":  cfg.ReadinessThreshold,
+		"taxonomy_depth":       cfg.TaxonomyDepth,
+		"request_id_length":    cfg.RequestIDLength,
+		"site_domain":          redactURL(cfg.SiteDomain),
+		"metadata_title":       cfg.MetadataTitle,
+		"metadata_description": cfg.MetadataDescription,
+		"metadata_keywords":    cfg.MetadataKeywords,
+		"sparkline_frequency":  cfg.SparklineFrequency,
+		"sparkline_periods":    cfg.SparklinePeriods,
+		"sparkline_years":      cfg.SparklineYears,
+		"sparkline_max_points": cfg.SparklineMaxPoints,
+		"sparkline_svg":        cfg.SparklineSVG,
+		"otlp_endpoint":        redactURL(cfg.OTLPEndpoint),
+		"otlp_export_interval": cfg.OTLPExportInterval.String(),
+		"debug_timeline":       cfg.DebugTimeline,
+		"mandatory_components": cfg.MandatoryComponents,
+		"cache_control":        cfg.CacheControl,
+		"batch_concurrency":    cfg.BatchConcurrency,
+		"batch_max_uris":       cfg.BatchMaxURIs,
+		"export_concurrency":   cfg.ExportConcurrency,
+		"export_max_pages":     cfg.ExportMaxPages,
+		"feed_max_items":       cfg.FeedMaxItems,
+		"feed_taxonomy_depth":  cfg.FeedTaxonomyDepth,
+		"feed_max_links":       cfg.FeedMaxLinks,
+		"feed_cache_ttl":       cfg.FeedCacheTTL.String(),
+		"sitemap_interval":     cfg.SitemapInterval.String(),
+		"sitemap_max_urls":     cfg.SitemapMaxURLs,
+		"sitemap_welsh_domain": redactURL(cfg.SitemapWelshDomain),
+		"diff_zebedee_url":     redactURL(cfg.DiffZebedeeURL),
+		"diff_content_dir":     cfg.DiffContentDir,
+		"diff_replay_dir":      cfg.DiffReplayDir,
+		"diff_ignore":          cfg.DiffIgnore,
+	}
+}
+
+func validateURL(name string, value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid URL: %s", name, err)
+	}
+	if len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return fmt.Errorf("%s must be an absolute URL, found %q", name, redactURL(value))
+	}
+	return nil
+}
+
+// redactURL removes any password from the URL.
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+	return u.String()
+}
+
+// listValue is a flag.Value for comma separated lists.
+type listValue []string
+
+func (l *listValue) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *listValue) Set(value string) error {
+	*l = strings.Split(value, ",")
+	return nil
+}
@@ -0,0 +1,110 @@
+package fields
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Param is the query parameter listing the fields of a page to resolve, e.g. ?fields=breadcrumb,taxonomy.
+const Param = "fields"
+
+// Set is the fields of a page requested. A nil Set requests every field.
+type Set map[string]bool
+
+// Parse returns the set of comma separated fields, or nil if none are listed.
+func Parse(value string) Set {
+	var set Set
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); len(field) > 0 {
+			if set == nil {
+				set = make(Set)
+			}
+			set[field] = true
+		}
+	}
+	return set
+}
+
+// FromRequest returns the fields requested in the request query.
+func FromRequest(req *http.Request) Set {
+	return Parse(req.URL.Query().Get(Param))
+}
+
+// Includes returns true if the field is requested.
+func (set Set) Includes(field string) bool {
+	return set == nil || set[field]
+}
+
+// Validate returns an error if any requested field is not one of the supported fields.
+func (set Set) Validate(supported []string) error {
+	var unsupported []string
+	for field := range set {
+		if !contains(supported, field) {
+			unsupported = append(unsupported, field)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return fmt.Errorf("unsupported fields %s, supported fields are %s", strings.Join(unsupported, ","), strings.Join(supported, ","))
+	}
+	return nil
+}
+
+// Omit removes the fields that are not requested from the JSON object. paths maps each field to the dot separated path
+// of its key in the object, e.g. data.headlineFigures.
+func Omit(data []byte, set Set, paths map[string]string) ([]byte, error) {
+	if set == nil {
+		return data, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	for field, path := range paths {
+		if set.Includes(field) {
+			continue
+		}
+		if err := omitPath(object, strings.Split(path, ".")); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(object)
+}
+
+func omitPath(object map[string]json.RawMessage, path []string) error {
+	if len(path) == 1 {
+		delete(object, path[0])
+		return nil
+	}
+
+	value, ok := object[path[0]]
+	if !ok {
+		return nil
+	}
+
+	var child map[string]json.RawMessage
+	if err := json.Unmarshal(value, &child); err != nil {
+		return err
+	}
+	if err := omitPath(child, path[1:]); err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(child)
+	object[path[0]] = value
+	return err
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
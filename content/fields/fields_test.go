@@ -0,0 +1,51 @@
+package fields
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSet(t *testing.T) {
+
+	Convey("Should include every field when none are requested.", t, func() {
+		set := Parse(" , ")
+
+		So(set, ShouldBeNil)
+		So(set.Includes("taxonomy"), ShouldBeTrue)
+		So(set.Validate([]string{"taxonomy"}), ShouldBeNil)
+	})
+
+	Convey("Should include only the requested fields.", t, func() {
+		set := Parse("breadcrumb, taxonomy")
+
+		So(set.Includes("taxonomy"), ShouldBeTrue)
+		So(set.Includes("breadcrumb"), ShouldBeTrue)
+		So(set.Includes("headlineFigures"), ShouldBeFalse)
+	})
+
+	Convey("Should reject unsupported fields.", t, func() {
+		err := Parse("taxonomy,releases,featured").Validate([]string{"taxonomy", "breadcrumb"})
+
+		So(err.Error(), ShouldEqual, "unsupported fields featured,releases, supported fields are taxonomy,breadcrumb")
+	})
+}
+
+func TestOmit(t *testing.T) {
+	paths := map[string]string{"taxonomy": "taxonomy", "headlineFigures": "data.headlineFigures"}
+	data := []byte(`{"uri":"/","taxonomy":[],"data":{"headlineFigures":[],"releases":[]}}`)
+
+	Convey("Should remove the fields that are not requested.", t, func() {
+		omitted, err := Omit(data, Parse("taxonomy"), paths)
+
+		So(err, ShouldBeNil)
+		So(string(omitted), ShouldEqual, `{"data":{"releases":[]},"taxonomy":[],"uri":"/"}`)
+	})
+
+	Convey("Should return the data unchanged when every field is requested.", t, func() {
+		omitted, err := Omit(data, nil, paths)
+
+		So(err, ShouldBeNil)
+		So(string(omitted), ShouldEqual, string(data))
+	})
+}
@@ -0,0 +1,195 @@
+package homePage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-content-resolver/content/metadata"
+	"github.com/ONSdigital/dp-content-resolver/model"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+	renderModel "github.com/ONSdigital/dp-frontend-models/model"
+	"github.com/ONSdigital/go-ns/common"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testOptions resolve the homepage with the default configuration.
+var testOptions = Options{
+	TaxonomyDepth: 2,
+	Metadata:      metadata.Defaults{SiteDomain: "https://www.ons.gov.uk"},
+}
+
+// zebedeeServiceMock returns the timeseries and data pages held for each uri.
+type zebedeeServiceMock struct {
+	timeseries map[string]*zebedeeModel.TimeseriesPage
+	data       map[string][]byte
+}
+
+func (mock *zebedeeServiceMock) GetData(ctx context.Context, uri string, requestContextID string) ([]byte, string, *common.ONSError) {
+	if data, ok := mock.data[uri]; ok {
+		return data, "", nil
+	}
+	return nil, "", common.NewONSError(errors.New("not found"), "")
+}
+
+func (mock *zebedeeServiceMock) GetTaxonomy(ctx context.Context, uri string, depth int, requestContextID string) ([]zebedeeModel.ContentNode, *common.ONSError) {
+	return nil, nil
+}
+
+func (mock *zebedeeServiceMock) GetParents(ctx context.Context, uri string, requestContextID string) ([]zebedeeModel.ContentNode, *common.ONSError) {
+	return nil, nil
+}
+
+func (mock *zebedeeServiceMock) GetTimeSeries(ctx context.Context, uri string, requestContextID string) (*zebedeeModel.TimeseriesPage, *common.ONSError) {
+	if page, ok := mock.timeseries[uri]; ok {
+		return page, nil
+	}
+	return nil, common.NewONSError(errors.New("not found"), "")
+}
+
+func section(index int, statisticsURI string, themeURI string) *zebedeeModel.HomeSection {
+	return &zebedeeModel.HomeSection{
+		Index:      index,
+		Theme:      &zebedeeModel.Link{URI: themeURI},
+		Statistics: &zebedeeModel.Link{URI: statisticsURI},
+	}
+}
+
+func TestResolveHeadlineSections(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	reqContextIDGen := requests.NewContentIDGenerator(req)
+
+	resolver := NewResolver(&zebedeeServiceMock{
+		timeseries: map[string]*zebedeeModel.TimeseriesPage{
+			"/economy/cpi":     {URI: "/economy/cpi"},
+			"/economy/gdp":     {URI: "/economy/gdp"},
+			"/employment/rate": {URI: "/employment/rate"},
+		},
+		data: map[string][]byte{
+			"/employment": []byte(`{"uri": "/employment", "description": {"title": "Employment"}}`),
+		},
+	}, testOptions)
+
+	Convey("Should order headline figures by their section index.", t, func() {
+		sections := []*zebedeeModel.HomeSection{
+			section(2, "/employment/rate", "/employment"),
+			section(0, "/economy/gdp", "/economy"),
+			section(1, "/economy/cpi", "/economy"),
+		}
+
+		headlines := resolver.resolveHeadlineSections(req.Context(), sections, reqContextIDGen)
+
+		So(len(headlines), ShouldEqual, 3)
+		So(headlines[0].headline.URI, ShouldEqual, "/economy/gdp")
+		So(headlines[1].headline.URI, ShouldEqual, "/economy/cpi")
+		So(headlines[2].headline.URI, ShouldEqual, "/employment/rate")
+		So(headlines[2].headline.Index, ShouldEqual, 2)
+	})
+
+	Convey("Should resolve theme titles from the taxonomy, falling back to zebedee.", t, func() {
+		sections := []*zebedeeModel.HomeSection{
+			section(0, "/economy/gdp", "/economy"),
+			section(1, "/employment/rate", "/employment"),
+		}
+		taxonomy := []renderModel.TaxonomyNode{{URI: "/economy", Title: "Economy"}}
+
+		headlines := resolver.resolveHeadlineSections(req.Context(), sections, reqContextIDGen)
+		resolver.resolveThemes(req, headlines, taxonomy, reqContextIDGen)
+
+		So(headlines[0].headline.Theme.Title, ShouldEqual, "Economy")
+		So(headlines[1].headline.Theme.Title, ShouldEqual, "Employment")
+	})
+}
+
+func TestResolveWarnings(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+
+	resolver := NewResolver(&zebedeeServiceMock{
+		timeseries: map[string]*zebedeeModel.TimeseriesPage{
+			"/economy/gdp":     {URI: "/economy/gdp"},
+			"/employment/rate": {URI: "/employment/rate"},
+		},
+		data: map[string][]byte{
+			"/employment": []byte(`{"uri": "/employment", "description": {"title": "Employment"}}`),
+		},
+	}, testOptions)
+
+	Convey("Should return a warning for each component that failed to resolve.", t, func() {
+		homepage := zebedeeModel.HomePage{URI: "/", Sections: []*zebedeeModel.HomeSection{
+			section(0, "/economy/gdp", "/economy"),
+			section(1, "/economy/cpi", "/economy"),
+			section(2, "/employment/rate", "/employment"),
+		}}
+
+		data, warnings, err := resolver.Resolve(req, homepage, requests.NewContentIDGenerator(req))
+		So(err, ShouldBeNil)
+		So(warnings, ShouldResemble, []model.Warning{
+			{Component: ComponentHeadline, ResolveURI: "/economy/cpi", Error: "not found"},
+			{Component: ComponentTheme, ResolveURI: "/economy", Error: "not found"},
+		})
+
+		var resolved page
+		json.Unmarshal(data, &resolved)
+		So(resolved.Degraded, ShouldBeTrue)
+		So(resolved.Warnings, ShouldResemble, warnings)
+		So(len(resolved.Data.HeadlineFigures), ShouldEqual, 2)
+	})
+
+	Convey("Should report an empty list of warnings when every component resolves.", t, func() {
+		data, warnings, err := resolver.Resolve(req, zebedeeModel.HomePage{URI: "/"}, requests.NewContentIDGenerator(req))
+		So(err, ShouldBeNil)
+		So(warnings, ShouldBeEmpty)
+		So(string(data), ShouldContainSubstring, `"degraded":false,"warnings":[]`)
+	})
+}
+
+func TestResolveFields(t *testing.T) {
+	resolver := NewResolver(&zebedeeServiceMock{}, testOptions)
+
+	Convey("Should neither resolve nor include fields that are not requested.", t, func() {
+		req := httptest.NewRequest("GET", "/?fields=breadcrumb", nil)
+		homepage := zebedeeModel.HomePage{URI: "/", Sections: []*zebedeeModel.HomeSection{
+			section(0, "/economy/gdp", "/economy"),
+		}}
+
+		data, warnings, err := resolver.Resolve(req, homepage, requests.NewContentIDGenerator(req))
+		So(err, ShouldBeNil)
+		So(warnings, ShouldBeEmpty)
+
+		var resolved map[string]interface{}
+		json.Unmarshal(data, &resolved)
+		So(resolved, ShouldContainKey, "breadcrumb")
+		So(resolved, ShouldNotContainKey, "taxonomy")
+		So(resolved, ShouldNotContainKey, "metadata")
+		So(resolved["data"], ShouldNotContainKey, "headlineFigures")
+	})
+
+	Convey("Should resolve the metadata from the page description.", t, func() {
+		req := httptest.NewRequest("GET", "/?fields=metadata", nil)
+		homepage := zebedeeModel.HomePage{URI: "/", Description: zebedeeModel.PageDescription{Title: "Home"}}
+
+		data, _, err := resolver.Resolve(req, homepage, requests.NewContentIDGenerator(req))
+		So(err, ShouldBeNil)
+
+		var resolved page
+		json.Unmarshal(data, &resolved)
+		So(resolved.Metadata.Title, ShouldEqual, "Home")
+		So(resolved.Metadata.CanonicalURI, ShouldEqual, "https://www.ons.gov.uk/")
+		So(string(data), ShouldContainSubstring, `"metadata":{"title":"Home"`)
+	})
+}
+
+func TestFindTaxonomyNode(t *testing.T) {
+
+	Convey("Should find nested taxonomy nodes by uri.", t, func() {
+		taxonomy := []renderModel.TaxonomyNode{
+			{URI: "/economy", Children: []renderModel.TaxonomyNode{{URI: "/economy/inflation", Title: "Inflation"}}},
+		}
+
+		So(findTaxonomyNode(taxonomy, "/economy/inflation").Title, ShouldEqual, "Inflation")
+		So(findTaxonomyNode(taxonomy, "/business"), ShouldBeNil)
+	})
+}
@@ -0,0 +1,37 @@
+package homePage
+
+import (
+	"github.com/ONSdigital/dp-content-resolver/model"
+	"github.com/ONSdigital/dp-frontend-models/model/homepage"
+)
+
+// page is the resolved homepage. It extends the renderer model with the fields resolved by this service that the
+// renderer model does not yet carry.
+type page struct {
+	homepage.Page
+	// Metadata replaces the renderer model metadata, which has no canonical URI, release date or page type.
+	Metadata model.Metadata `json:"metadata"`
+	model.Status
+	Data data `json:"data"`
+}
+
+// data is the homepage specific data of the resolved page.
+type data struct {
+	homepage.Homepage
+	HeadlineFigures []*headlineFigure `json:"headlineFigures"`
+}
+
+// headlineFigure is a resolved headline figure along with the position and theme of its homepage section. The
+// renderer model excludes the start and end dates from its JSON so they are repeated here.
+type headlineFigure struct {
+	*homepage.HeadlineFigure
+	Index int         `json:"index"`
+	Theme *model.Link `json:"theme,omitempty"`
+	// StartDate and EndDate are the dates of the first and last points of the sparkline. They describe the sparkline,
+	// not the change, which covers the period from its previous date to the end date.
+	StartDate string  `json:"startDate"`
+	EndDate   string  `json:"endDate"`
+	Change    *change `json:"change,omitempty"`
+	// SparklineSVG is only set if embedding SVG sparklines is enabled.
+	SparklineSVG string `json:"sparklineSvg,omitempty"`
+}
@@ -1,12 +1,21 @@
 package homePage
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 
+	"github.com/ONSdigital/dp-content-resolver/content/fields"
+	"github.com/ONSdigital/dp-content-resolver/content/metadata"
+	"github.com/ONSdigital/dp-content-resolver/metrics"
+	"github.com/ONSdigital/dp-content-resolver/model"
 	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/sparkline"
+	"github.com/ONSdigital/dp-content-resolver/tracing"
 	"github.com/ONSdigital/dp-content-resolver/zebedee"
 	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
 	renderModel "github.com/ONSdigital/dp-frontend-models/model"
@@ -15,13 +24,63 @@ import (
 	"github.com/ONSdigital/go-ns/log"
 )
 
-// ZebedeeService service provides functionality for sending HTTP Get requests to Zebedee.
-var ZebedeeService zebedee.Service
+// Components of the homepage that may fail to resolve without failing the whole resolve.
+const (
+	ComponentTaxonomy   = "taxonomy"
+	ComponentBreadcrumb = "breadcrumb"
+	ComponentHeadline   = "headline"
+	ComponentTheme      = "theme"
+)
+
+// Fields of the homepage that may be requested using the fields query parameter.
+const (
+	FieldMetadata        = "metadata"
+	FieldTaxonomy        = "taxonomy"
+	FieldBreadcrumb      = "breadcrumb"
+	FieldHeadlineFigures = "headlineFigures"
+)
+
+// Fields lists the fields of the homepage that may be requested. Fields that are not requested are neither resolved
+// nor included in the resolved page.
+var Fields = []string{FieldMetadata, FieldTaxonomy, FieldBreadcrumb, FieldHeadlineFigures}
+
+// fieldPaths is the path of each field in the resolved page JSON.
+var fieldPaths = map[string]string{
+	FieldMetadata:        "metadata",
+	FieldTaxonomy:        "taxonomy",
+	FieldBreadcrumb:      "breadcrumb",
+	FieldHeadlineFigures: "data.headlineFigures",
+}
+
+var headlineFailures = metrics.NewCounter("headline_resolve_failures_total", "Number of homepage headline figures that failed to resolve.")
+
+// Options controls how the homepage is resolved.
+type Options struct {
+	// TaxonomyDepth is the depth of the taxonomy requested from Zebedee for the homepage.
+	TaxonomyDepth int
+	// Sparkline is the sparkline configuration applied to each headline figure.
+	Sparkline SparklineOptions
+	// Metadata holds the site-wide values used when Zebedee does not provide the page metadata.
+	Metadata metadata.Defaults
+}
+
+// Resolver resolves homepages using the Zebedee service it was created with.
+type Resolver struct {
+	zebedeeService zebedee.Service
+	options        Options
+}
+
+// NewResolver creates a homepage resolver requesting content from the Zebedee service.
+func NewResolver(zebedeeService zebedee.Service, options Options) *Resolver {
+	return &Resolver{zebedeeService: zebedeeService, options: options}
+}
 
 type resolvedHeadlines []*resolvedHeadline
 
 type resolvedHeadline struct {
-	headline *homepage.HeadlineFigure
+	index    int
+	uri      string
+	headline *headlineFigure
 	err      error
 	meta     log.Data
 }
@@ -50,61 +109,105 @@ func (r *resolvedHeadline) isError() bool {
 	return r.err != nil
 }
 
+// byIndex sorts resolved headlines into the section order set by the editors.
+type byIndex resolvedHeadlines
+
+func (r byIndex) Len() int           { return len(r) }
+func (r byIndex) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r byIndex) Less(i, j int) bool { return r[i].index < r[j].index }
+
 // Resolve the given page data.
-func Resolve(req *http.Request, pageToResolve zebedeeModel.HomePage, reqContentIDGen requests.ContextIDGenerator) (resolvedPageData []byte, err error) {
-	var resolvedPage = homepage.Page{URI: pageToResolve.URI}
+func (resolver *Resolver) Resolve(req *http.Request, pageToResolve zebedeeModel.HomePage, reqContentIDGen *requests.ContextIDGenerator) (resolvedPageData []byte, warnings []model.Warning, err error) {
+	pageType := pageToResolve.Type
+	if len(pageType) == 0 {
+		pageType = zebedee.HomePage
+	}
+
+	var resolvedPage = page{Page: homepage.Page{URI: pageToResolve.URI, Type: pageType}}
+	resolvedPage.Metadata = resolver.options.Metadata.Map(pageToResolve.URI, pageType, pageToResolve.Description)
+
 	var taxonomyErr *common.ONSError
 	var breadcrumbErr *common.ONSError
 	var headlines resolvedHeadlines
 
+	requested := fields.FromRequest(req)
 	wg := new(sync.WaitGroup)
-	wg.Add(3)
 
-	go func() {
-		resolvedPage.Taxonomy, taxonomyErr = resolveTaxonomy(resolvedPage.URI, reqContentIDGen)
-		wg.Done()
-	}()
+	if requested.Includes(FieldTaxonomy) {
+		wg.Add(1)
+		go func() {
+			resolvedPage.Taxonomy, taxonomyErr = resolver.resolveTaxonomy(req.Context(), resolvedPage.URI, reqContentIDGen)
+			wg.Done()
+		}()
+	}
 
-	go func() {
-		resolvedPage.Breadcrumb, breadcrumbErr = resolveParents(resolvedPage.URI, reqContentIDGen)
-		wg.Done()
-	}()
+	if requested.Includes(FieldBreadcrumb) {
+		wg.Add(1)
+		go func() {
+			resolvedPage.Breadcrumb, breadcrumbErr = resolver.resolveParents(req.Context(), resolvedPage.URI, reqContentIDGen)
+			wg.Done()
+		}()
+	}
 
-	go func() {
-		headlines = resolveHeadlineSections(pageToResolve.Sections, reqContentIDGen)
-		wg.Done()
-	}()
+	if requested.Includes(FieldHeadlineFigures) {
+		wg.Add(1)
+		go func() {
+			headlines = resolver.resolveHeadlineSections(req.Context(), pageToResolve.Sections, reqContentIDGen.Child())
+			wg.Done()
+		}()
+	}
 
 	wg.Wait() // wait for all the resolve jobs to complete.
 
+	themeWarnings := resolver.resolveThemes(req, headlines, resolvedPage.Taxonomy, reqContentIDGen.Child())
+
 	if taxonomyErr != nil {
 		log.ErrorR(req, taxonomyErr, nil)
+		warnings = append(warnings, warning(ComponentTaxonomy, resolvedPage.URI, taxonomyErr.RootError))
 	}
 
 	if breadcrumbErr != nil {
 		log.ErrorR(req, breadcrumbErr, nil)
+		warnings = append(warnings, warning(ComponentBreadcrumb, resolvedPage.URI, breadcrumbErr.RootError))
 	}
 
 	if errorCount := headlines.countErrors(); errorCount > 0 {
+		headlineFailures.Add(float64(errorCount))
 		log.ErrorR(req, fmt.Errorf("One of more headline sections failed to resolve."), log.Data{
 			"totalHeadLineResolves":  len(pageToResolve.Sections) + 1,
 			"failedHeadLineResolves": errorCount,
 		})
 	}
 
-	resolvedPage.Data.HeadlineFigures = make([]*homepage.HeadlineFigure, 0)
+	resolvedPage.Data.HeadlineFigures = make([]*headlineFigure, 0)
 	for _, resolvedItem := range headlines {
 		if resolvedItem.isError() {
 			log.ErrorR(req, resolvedItem.err, resolvedItem.meta)
+			warnings = append(warnings, warning(ComponentHeadline, resolvedItem.uri, resolvedItem.err))
 		} else {
 			resolvedPage.Data.HeadlineFigures = append(resolvedPage.Data.HeadlineFigures, resolvedItem.headline)
 		}
 	}
-	resolvedPageData, err = json.Marshal(resolvedPage)
+	warnings = append(warnings, themeWarnings...)
+
+	resolvedPage.Status = model.NewStatus(warnings)
+	if resolvedPageData, err = json.Marshal(resolvedPage); err != nil {
+		return
+	}
+	resolvedPageData, err = fields.Omit(resolvedPageData, requested, fieldPaths)
 	return
 }
 
-func resolveHeadlineSections(pageSections []*zebedeeModel.HomeSection, reqContextIDGen requests.ContextIDGenerator) resolvedHeadlines {
+// warning describes the failure to resolve a component of the homepage.
+func warning(component string, uri string, err error) model.Warning {
+	return model.Warning{Component: component, ResolveURI: uri, Error: err.Error()}
+}
+
+func (resolver *Resolver) resolveHeadlineSections(ctx context.Context, pageSections []*zebedeeModel.HomeSection, reqContextIDGen *requests.ContextIDGenerator) resolvedHeadlines {
+	ctx, span := tracing.StartSpan(ctx, "homepage.headlines", tracing.KindInternal)
+	span.SetAttribute("sections", len(pageSections))
+	defer span.Finish()
+
 	results := make(resolvedHeadlines, len(pageSections))
 	wg := new(sync.WaitGroup)
 	wg.Add(len(pageSections))
@@ -115,18 +218,25 @@ func resolveHeadlineSections(pageSections []*zebedeeModel.HomeSection, reqContex
 			var timeSeriesPage *zebedeeModel.TimeseriesPage
 			var onsError *common.ONSError
 			var result *resolvedHeadline
-			timeSeriesPage, onsError = ZebedeeService.GetTimeSeries(section.Statistics.URI, reqContextIDGen.Generate())
+			timeSeriesPage, onsError = resolver.getTimeSeries(ctx, section.Statistics.URI, reqContextIDGen)
 
 			if onsError != nil {
+				span.SetError(errors.New("one or more headline sections failed to resolve"))
 				onsError.AddParameter("resolveURI", section.Statistics.URI)
 				onsError.AddParameter("description", "Failed to resolve headline section.")
 
 				result = &resolvedHeadline{
-					err:  onsError.RootError,
-					meta: onsError.Parameters,
+					index: section.Index,
+					uri:   section.Statistics.URI,
+					err:   onsError.RootError,
+					meta:  onsError.Parameters,
 				}
 			} else {
-				result = &resolvedHeadline{headline: mapTimeseriesToHeadlineFigure(timeSeriesPage)}
+				result = &resolvedHeadline{index: section.Index, uri: section.Statistics.URI, headline: mapTimeseriesToHeadlineFigure(timeSeriesPage, resolver.options.Sparkline)}
+				result.headline.Index = section.Index
+				if section.Theme != nil {
+					result.headline.Theme = &model.Link{Title: section.Theme.Title, URI: section.Theme.URI}
+				}
 			}
 
 			results[index] = result
@@ -134,11 +244,106 @@ func resolveHeadlineSections(pageSections []*zebedeeModel.HomeSection, reqContex
 		}(i, section)
 	}
 	wg.Wait()
+
+	sort.Stable(byIndex(results))
 	return results
 }
 
-func mapTimeseriesToHeadlineFigure(page *zebedeeModel.TimeseriesPage) (figure *homepage.HeadlineFigure) {
-	figure = &homepage.HeadlineFigure{
+// resolveThemes sets the title of each headline theme link that Zebedee did not provide a title for. Titles are
+// taken from the resolved taxonomy where possible, otherwise the theme page is requested from Zebedee. A warning is
+// returned for each theme that could not be resolved.
+func (resolver *Resolver) resolveThemes(req *http.Request, headlines resolvedHeadlines, taxonomy []renderModel.TaxonomyNode, reqContextIDGen *requests.ContextIDGenerator) []model.Warning {
+	ctx, span := tracing.StartSpan(req.Context(), "homepage.themes", tracing.KindInternal)
+	defer span.Finish()
+
+	var warnings []model.Warning
+	var mutex sync.Mutex
+	addWarning := func(uri string, err error) {
+		mutex.Lock()
+		warnings = append(warnings, warning(ComponentTheme, uri, err))
+		mutex.Unlock()
+	}
+
+	wg := new(sync.WaitGroup)
+
+	for _, item := range headlines {
+		if item.isError() || item.headline.Theme == nil || len(item.headline.Theme.Title) > 0 {
+			continue
+		}
+
+		theme := item.headline.Theme
+		if node := findTaxonomyNode(taxonomy, theme.URI); node != nil {
+			theme.Title = node.Title
+			continue
+		}
+
+		wg.Add(1)
+		go func(theme *model.Link) {
+			defer wg.Done()
+
+			data, _, err := resolver.zebedeeService.GetData(ctx, theme.URI, reqContextIDGen.Generate())
+			if err != nil {
+				err.AddParameter("resolveURI", theme.URI)
+				err.AddParameter("description", "Failed to resolve headline theme.")
+				log.ErrorR(req, err.RootError, err.Parameters)
+				addWarning(theme.URI, err.RootError)
+				return
+			}
+
+			var themePage zebedeeModel.ContentNode
+			if unmarshalErr := json.Unmarshal(data, &themePage); unmarshalErr != nil {
+				log.ErrorR(req, unmarshalErr, log.Data{"resolveURI": theme.URI})
+				addWarning(theme.URI, unmarshalErr)
+				return
+			}
+			theme.Title = themePage.Description.Title
+		}(theme)
+	}
+	wg.Wait()
+
+	sort.SliceStable(warnings, func(i, j int) bool { return warnings[i].ResolveURI < warnings[j].ResolveURI })
+	return warnings
+}
+
+// findTaxonomyNode searches the taxonomy tree for the node with the given uri.
+func findTaxonomyNode(nodes []renderModel.TaxonomyNode, uri string) *renderModel.TaxonomyNode {
+	for i := range nodes {
+		if nodes[i].URI == uri {
+			return &nodes[i]
+		}
+		if node := findTaxonomyNode(nodes[i].Children, uri); node != nil {
+			return node
+		}
+	}
+	return nil
+}
+
+// getTimeSeries gets the timeseries page for a headline figure. The full page is requested when a sparkline frequency
+// is configured as the Zebedee series only contains its default frequency.
+func (resolver *Resolver) getTimeSeries(ctx context.Context, uri string, reqContextIDGen *requests.ContextIDGenerator) (*zebedeeModel.TimeseriesPage, *common.ONSError) {
+	if len(resolver.options.Sparkline.Frequency) == 0 {
+		return resolver.zebedeeService.GetTimeSeries(ctx, uri, reqContextIDGen.Generate())
+	}
+
+	requestContextID := reqContextIDGen.Generate()
+	data, _, err := resolver.zebedeeService.GetData(ctx, uri, requestContextID)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeSeriesPage *zebedeeModel.TimeseriesPage
+	if unmarshalErr := json.Unmarshal(data, &timeSeriesPage); unmarshalErr != nil {
+		err = common.NewONSError(unmarshalErr, "Error unmarshalling timeseries page json.")
+		err.AddParameter("requestContextId", requestContextID)
+		return nil, err
+	}
+	return timeSeriesPage, nil
+}
+
+// mapTimeseriesToHeadlineFigure maps the timeseries page to a headline figure. The change over period is computed from
+// the full series before the sparkline options are applied.
+func mapTimeseriesToHeadlineFigure(page *zebedeeModel.TimeseriesPage, options SparklineOptions) *headlineFigure {
+	figure := &homepage.HeadlineFigure{
 		Title: page.Description.Title,
 	}
 
@@ -151,23 +356,36 @@ func mapTimeseriesToHeadlineFigure(page *zebedeeModel.TimeseriesPage) (figure *h
 		Figure:  page.Description.Number,
 	}
 
-	figure.SparklineData = make([]homepage.SparklineData, len(page.Series))
-	for i, seriesItem := range page.Series {
-		figure.SparklineData[i] = homepage.SparklineData{
-			Name:    seriesItem.Name,
-			StringY: seriesItem.StringY,
-			Y:       seriesItem.Y,
-		}
+	series := options.series(page)
+	figure.SparklineData = options.apply(series)
+
+	if len(figure.SparklineData) > 0 {
+		figure.StartDate = figure.SparklineData[0].Name
+		figure.EndDate = figure.SparklineData[len(figure.SparklineData)-1].Name
+	}
+
+	headline := &headlineFigure{
+		HeadlineFigure: figure,
+		StartDate:      figure.StartDate,
+		EndDate:        figure.EndDate,
+		Change:         computeChange(series),
 	}
 
-	return figure
+	if options.EmbedSVG {
+		headline.SparklineSVG = string(sparkline.Render(headline.chart(), sparkline.DefaultOptions))
+	}
+	return headline
 }
 
-func resolveTaxonomy(uri string, reqContextIDGen requests.ContextIDGenerator) ([]renderModel.TaxonomyNode, *common.ONSError) {
+func (resolver *Resolver) resolveTaxonomy(ctx context.Context, uri string, reqContextIDGen *requests.ContextIDGenerator) ([]renderModel.TaxonomyNode, *common.ONSError) {
+	ctx, span := tracing.StartSpan(ctx, "homepage.taxonomy", tracing.KindInternal)
+	defer span.Finish()
+
 	var rendererTaxonomyList []renderModel.TaxonomyNode
-	zebedeeContentNodeList, err := ZebedeeService.GetTaxonomy(uri, 2, reqContextIDGen.Generate())
+	zebedeeContentNodeList, err := resolver.zebedeeService.GetTaxonomy(ctx, uri, resolver.options.TaxonomyDepth, reqContextIDGen.Generate())
 
 	if err != nil {
+		span.SetError(err)
 		return rendererTaxonomyList, err
 	}
 
@@ -180,11 +398,15 @@ func resolveTaxonomy(uri string, reqContextIDGen requests.ContextIDGenerator) ([
 }
 
 // resolveParents get the parents data from zebedee and convert it into the renderer model.
-func resolveParents(uri string, reqContextIDGen requests.ContextIDGenerator) ([]renderModel.TaxonomyNode, *common.ONSError) {
+func (resolver *Resolver) resolveParents(ctx context.Context, uri string, reqContextIDGen *requests.ContextIDGenerator) ([]renderModel.TaxonomyNode, *common.ONSError) {
+	ctx, span := tracing.StartSpan(ctx, "homepage.breadcrumb", tracing.KindInternal)
+	defer span.Finish()
+
 	var taxonomyNodeList []renderModel.TaxonomyNode
-	zebedeeContentNodes, err := ZebedeeService.GetParents(uri, reqContextIDGen.Generate())
+	zebedeeContentNodes, err := resolver.zebedeeService.GetParents(ctx, uri, reqContextIDGen.Generate())
 
 	if err != nil {
+		span.SetError(err)
 		return taxonomyNodeList, err
 	}
 
@@ -0,0 +1,206 @@
+package homePage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/sparkline"
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+	"github.com/ONSdigital/dp-frontend-models/model/homepage"
+	"github.com/ONSdigital/go-ns/common"
+)
+
+// Sparkline frequencies that can be chosen in place of the default Zebedee series.
+const (
+	FrequencyYears    = "years"
+	FrequencyQuarters = "quarters"
+	FrequencyMonths   = "months"
+)
+
+// SparklineOptions controls which points of a timeseries are sent as headline figure sparkline data.
+type SparklineOptions struct {
+	// Frequency of the series to use. Empty uses the series chosen by Zebedee.
+	Frequency string
+	// LastPeriods limits the sparkline to the most recent number of periods. Zero includes all periods.
+	LastPeriods int
+	// LastYears limits the sparkline to the most recent number of years. Zero includes all years.
+	LastYears int
+	// MaxPoints downsamples the sparkline to at most this many points. Zero disables downsampling.
+	MaxPoints int
+	// EmbedSVG includes the sparkline rendered as SVG in each headline figure.
+	EmbedSVG bool
+}
+
+var nonAlphanumeric = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+// series returns the full series of the timeseries page for the configured frequency, falling back to the Zebedee
+// series if the page has no data for that frequency.
+func (options SparklineOptions) series(page *zebedeeModel.TimeseriesPage) []homepage.SparklineData {
+	var entries []zebedeeModel.TimeSeriesEntry
+	switch options.Frequency {
+	case FrequencyYears:
+		entries = page.Years
+	case FrequencyQuarters:
+		entries = page.Quarters
+	case FrequencyMonths:
+		entries = page.Months
+	}
+
+	if len(entries) > 0 {
+		series := make([]homepage.SparklineData, len(entries))
+		for i, entry := range entries {
+			value, _ := strconv.ParseFloat(entry.Value, 32)
+			series[i] = homepage.SparklineData{Name: entry.Date, Y: float32(value), StringY: entry.Value}
+		}
+		return series
+	}
+
+	series := make([]homepage.SparklineData, len(page.Series))
+	for i, seriesItem := range page.Series {
+		series[i] = homepage.SparklineData{
+			Name:    seriesItem.Name,
+			StringY: seriesItem.StringY,
+			Y:       seriesItem.Y,
+		}
+	}
+	return series
+}
+
+// apply restricts the series to the configured window and downsamples it to the configured maximum number of points.
+func (options SparklineOptions) apply(series []homepage.SparklineData) []homepage.SparklineData {
+	if options.LastPeriods > 0 && len(series) > options.LastPeriods {
+		series = series[len(series)-options.LastPeriods:]
+	}
+
+	if options.LastYears > 0 && len(series) > 0 {
+		if latestYear, ok := year(series[len(series)-1]); ok {
+			start := len(series)
+			for start > 0 {
+				if pointYear, ok := year(series[start-1]); ok && pointYear <= latestYear-options.LastYears {
+					break
+				}
+				start--
+			}
+			series = series[start:]
+		}
+	}
+
+	if options.MaxPoints > 0 {
+		series = downsample(series, options.MaxPoints)
+	}
+	return series
+}
+
+// year parses the year from the start of a series point name, e.g. "1989 JAN", "1989 Q1" or "1989".
+func year(point homepage.SparklineData) (int, bool) {
+	fields := strings.Fields(point.Name)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	value, err := strconv.Atoi(fields[0])
+	return value, err == nil
+}
+
+// downsample reduces the series to at most maxPoints using the largest triangle three buckets algorithm, which keeps
+// the first and last points and the points that best preserve the visual shape of the line.
+func downsample(series []homepage.SparklineData, maxPoints int) []homepage.SparklineData {
+	if maxPoints >= len(series) {
+		return series
+	}
+	if maxPoints == 1 {
+		return series[len(series)-1:]
+	}
+	if maxPoints == 2 {
+		return []homepage.SparklineData{series[0], series[len(series)-1]}
+	}
+
+	sampled := make([]homepage.SparklineData, 0, maxPoints)
+	sampled = append(sampled, series[0])
+
+	bucketSize := float64(len(series)-2) / float64(maxPoints-2)
+	selected := 0
+
+	for bucket := 0; bucket < maxPoints-2; bucket++ {
+		start := int(float64(bucket)*bucketSize) + 1
+		end := int(float64(bucket+1)*bucketSize) + 1
+
+		// The average of the next bucket is the third point of each triangle.
+		nextStart := end
+		nextEnd := int(float64(bucket+2)*bucketSize) + 1
+		if nextEnd > len(series) {
+			nextEnd = len(series)
+		}
+		var averageX, averageY float64
+		for i := nextStart; i < nextEnd; i++ {
+			averageX += float64(i)
+			averageY += float64(series[i].Y)
+		}
+		count := float64(nextEnd - nextStart)
+		averageX /= count
+		averageY /= count
+
+		largestArea := -1.0
+		next := start
+		for i := start; i < end; i++ {
+			area := math.Abs((float64(selected)-averageX)*(float64(series[i].Y)-float64(series[selected].Y)) -
+				(float64(selected)-float64(i))*(averageY-float64(series[selected].Y)))
+			if area > largestArea {
+				largestArea = area
+				next = i
+			}
+		}
+
+		sampled = append(sampled, series[next])
+		selected = next
+	}
+
+	return append(sampled, series[len(series)-1])
+}
+
+// chart creates the sparkline chart for the headline figure.
+func (figure *headlineFigure) chart() sparkline.Chart {
+	return sparkline.Chart{
+		ID:      "sparkline" + nonAlphanumeric.ReplaceAllString(figure.URI, "-"),
+		Title:   figure.Title,
+		Summary: figure.summary(),
+		Points:  figure.SparklineData,
+	}
+}
+
+// summary describes the trend of the headline figure for readers who cannot see the sparkline.
+func (figure *headlineFigure) summary() string {
+	latest := figure.LatestFigure
+	summary := fmt.Sprintf("%s%s%s", latest.PreUnit, latest.Figure, latest.Unit)
+	if len(figure.EndDate) > 0 {
+		summary += " in " + figure.EndDate
+	}
+
+	if figure.Change != nil {
+		if figure.Change.Direction == directionSame {
+			summary += ", unchanged from " + figure.Change.PreviousDate
+		} else {
+			summary += fmt.Sprintf(", %s %s from %s", figure.Change.Direction, figure.Change.StringAbsolute, figure.Change.PreviousDate)
+		}
+	}
+	summary += "."
+
+	if len(figure.StartDate) > 0 && figure.StartDate != figure.EndDate {
+		summary += fmt.Sprintf(" Sparkline shows %s to %s.", figure.StartDate, figure.EndDate)
+	}
+	return summary
+}
+
+// RenderSparkline resolves the headline figure for the timeseries at the given uri and renders its sparkline as SVG.
+func (resolver *Resolver) RenderSparkline(ctx context.Context, uri string, reqContextIDGen *requests.ContextIDGenerator) ([]byte, *common.ONSError) {
+	timeSeriesPage, err := resolver.getTimeSeries(ctx, uri, reqContextIDGen)
+	if err != nil {
+		return nil, err
+	}
+	figure := mapTimeseriesToHeadlineFigure(timeSeriesPage, resolver.options.Sparkline)
+	return sparkline.Render(figure.chart(), sparkline.DefaultOptions), nil
+}
@@ -0,0 +1,124 @@
+package homePage
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+	"github.com/ONSdigital/dp-frontend-models/model/homepage"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// monthlySeries creates a monthly series of the given length ending in December 2016.
+func monthlySeries(length int) []homepage.SparklineData {
+	months := []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	series := make([]homepage.SparklineData, length)
+	for i := range series {
+		month := 12*2017 - length + i
+		y := float32(math.Sin(float64(i) / 10))
+		series[i] = homepage.SparklineData{
+			Name:    fmt.Sprintf("%d %s", month/12, months[month%12]),
+			Y:       y,
+			StringY: fmt.Sprintf("%.1f", y),
+		}
+	}
+	return series
+}
+
+func TestSparklineSeries(t *testing.T) {
+
+	Convey("Should use the zebedee series when no frequency is configured.", t, func() {
+		page := &zebedeeModel.TimeseriesPage{
+			Series: []zebedeeModel.TimeSeriesValue{{Name: "2016 SEP", Y: 1, StringY: "1.0"}},
+			Years:  []zebedeeModel.TimeSeriesEntry{{Date: "2016", Value: "2.0"}},
+		}
+
+		So(SparklineOptions{}.series(page), ShouldResemble, []homepage.SparklineData{{Name: "2016 SEP", Y: 1, StringY: "1.0"}})
+	})
+
+	Convey("Should use the configured frequency when the page has data for it.", t, func() {
+		page := &zebedeeModel.TimeseriesPage{
+			Series:   []zebedeeModel.TimeSeriesValue{{Name: "2016 SEP", Y: 1, StringY: "1.0"}},
+			Quarters: []zebedeeModel.TimeSeriesEntry{{Date: "2016 Q3", Value: "2.5"}},
+		}
+
+		So(SparklineOptions{Frequency: FrequencyQuarters}.series(page), ShouldResemble, []homepage.SparklineData{{Name: "2016 Q3", Y: 2.5, StringY: "2.5"}})
+		So(SparklineOptions{Frequency: FrequencyYears}.series(page)[0].Name, ShouldEqual, "2016 SEP")
+	})
+}
+
+func TestSparklineApply(t *testing.T) {
+
+	Convey("Should return the series unchanged by default.", t, func() {
+		series := monthlySeries(100)
+		So(SparklineOptions{}.apply(series), ShouldResemble, series)
+	})
+
+	Convey("Should limit the series to the last N periods.", t, func() {
+		series := monthlySeries(100)
+		actual := SparklineOptions{LastPeriods: 12}.apply(series)
+
+		So(len(actual), ShouldEqual, 12)
+		So(actual[0].Name, ShouldEqual, "2016 JAN")
+		So(actual[11].Name, ShouldEqual, "2016 DEC")
+	})
+
+	Convey("Should limit the series to the last N years.", t, func() {
+		series := monthlySeries(100)
+		actual := SparklineOptions{LastYears: 2}.apply(series)
+
+		So(len(actual), ShouldEqual, 24)
+		So(actual[0].Name, ShouldEqual, "2015 JAN")
+	})
+
+	Convey("Should downsample the series keeping the first and last points.", t, func() {
+		series := monthlySeries(800)
+		actual := SparklineOptions{MaxPoints: 50}.apply(series)
+
+		So(len(actual), ShouldEqual, 50)
+		So(actual[0], ShouldResemble, series[0])
+		So(actual[49], ShouldResemble, series[799])
+	})
+
+	Convey("Should downsample very small maximums to the end points.", t, func() {
+		series := monthlySeries(10)
+
+		So(downsample(series, 2), ShouldResemble, []homepage.SparklineData{series[0], series[9]})
+		So(downsample(series, 1), ShouldResemble, []homepage.SparklineData{series[9]})
+	})
+}
+
+func TestHeadlineFigureDates(t *testing.T) {
+
+	Convey("Should describe the sparkline with the dates and the latest period with the change.", t, func() {
+		page := &zebedeeModel.TimeseriesPage{URI: "/economy/cpi"}
+		for _, point := range monthlySeries(24) {
+			page.Series = append(page.Series, zebedeeModel.TimeSeriesValue{Name: point.Name, Y: point.Y, StringY: point.StringY})
+		}
+
+		figure := mapTimeseriesToHeadlineFigure(page, SparklineOptions{LastPeriods: 12, MaxPoints: 4})
+
+		So(figure.StartDate, ShouldEqual, page.Series[12].Name)
+		So(figure.EndDate, ShouldEqual, page.Series[23].Name)
+		So(figure.Change.PreviousDate, ShouldEqual, page.Series[22].Name)
+	})
+}
+
+func TestHeadlineFigureSummary(t *testing.T) {
+
+	Convey("Should describe the latest figure, its change and the sparkline range.", t, func() {
+		figure := mapTimeseriesToHeadlineFigure(&zebedeeModel.TimeseriesPage{
+			URI:         "/economy/cpi",
+			Description: zebedeeModel.PageDescription{Number: "1.0", Unit: "%"},
+			Series: []zebedeeModel.TimeSeriesValue{
+				{Name: "2016 JUL", Y: 0.6, StringY: "0.6"},
+				{Name: "2016 AUG", Y: 0.6, StringY: "0.6"},
+				{Name: "2016 SEP", Y: 1, StringY: "1.0"},
+			},
+		}, SparklineOptions{})
+
+		So(figure.summary(), ShouldEqual, "1.0% in 2016 SEP, up 0.4 from 2016 AUG. Sparkline shows 2016 JUL to 2016 SEP.")
+		So(figure.chart().ID, ShouldEqual, "sparkline-economy-cpi")
+	})
+}
@@ -0,0 +1,85 @@
+package homePage
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ONSdigital/dp-frontend-models/model/homepage"
+)
+
+// Directions of travel for a headline figure.
+const (
+	directionUp   = "up"
+	directionDown = "down"
+	directionSame = "same"
+)
+
+// change is the movement of a headline figure between the previous and latest periods of its series.
+type change struct {
+	PreviousFigure string   `json:"previousFigure"`
+	PreviousDate   string   `json:"previousDate"`
+	Absolute       float64  `json:"absolute"`
+	StringAbsolute string   `json:"stringAbsolute"`
+	Percentage     *float64 `json:"percentage,omitempty"`
+	Direction      string   `json:"direction"`
+}
+
+// computeChange calculates the change between the last two points in the series. Nil is returned if the series does
+// not have enough points to compare.
+func computeChange(series []homepage.SparklineData) *change {
+	if len(series) < 2 {
+		return nil
+	}
+
+	previous := series[len(series)-2]
+	latest := series[len(series)-1]
+	previousValue, previousPrecision := parseValue(previous)
+	latestValue, latestPrecision := parseValue(latest)
+
+	precision := previousPrecision
+	if latestPrecision > precision {
+		precision = latestPrecision
+	}
+
+	absolute := round(latestValue-previousValue, precision)
+	c := &change{
+		PreviousFigure: previous.StringY,
+		PreviousDate:   previous.Name,
+		Absolute:       absolute,
+		StringAbsolute: strconv.FormatFloat(math.Abs(absolute), 'f', precision, 64),
+		Direction:      directionSame,
+	}
+
+	if absolute > 0 {
+		c.Direction = directionUp
+	} else if absolute < 0 {
+		c.Direction = directionDown
+	}
+
+	if previousValue != 0 {
+		percentage := round((latestValue-previousValue)/math.Abs(previousValue)*100, 1)
+		c.Percentage = &percentage
+	}
+	return c
+}
+
+// parseValue returns the value of a series point and the number of decimal places it was published to. The string
+// value is preferred as the float value has lost precision.
+func parseValue(point homepage.SparklineData) (float64, int) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(point.StringY), 64)
+	if err != nil {
+		return float64(point.Y), 1
+	}
+
+	precision := 0
+	if i := strings.Index(point.StringY, "."); i >= 0 {
+		precision = len(strings.TrimSpace(point.StringY[i+1:]))
+	}
+	return value, precision
+}
+
+func round(value float64, precision int) float64 {
+	scale := math.Pow(10, float64(precision))
+	return math.Round(value*scale) / scale
+}
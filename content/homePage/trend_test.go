@@ -0,0 +1,55 @@
+package homePage
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-frontend-models/model/homepage"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestComputeChange(t *testing.T) {
+
+	Convey("Should return nil if the series has fewer than two points.", t, func() {
+		So(computeChange(nil), ShouldBeNil)
+		So(computeChange([]homepage.SparklineData{{Name: "2016 SEP", Y: 1, StringY: "1.0"}}), ShouldBeNil)
+	})
+
+	Convey("Should compute an upward change to the published precision.", t, func() {
+		series := []homepage.SparklineData{
+			{Name: "2016 AUG", Y: 0.6, StringY: "0.6"},
+			{Name: "2016 SEP", Y: 1, StringY: "1.0"},
+		}
+
+		actual := computeChange(series)
+
+		So(actual.Direction, ShouldEqual, directionUp)
+		So(actual.Absolute, ShouldEqual, 0.4)
+		So(actual.StringAbsolute, ShouldEqual, "0.4")
+		So(*actual.Percentage, ShouldEqual, 66.7)
+		So(actual.PreviousFigure, ShouldEqual, "0.6")
+		So(actual.PreviousDate, ShouldEqual, "2016 AUG")
+	})
+
+	Convey("Should compute a downward change and omit the percentage when the previous value is zero.", t, func() {
+		series := []homepage.SparklineData{
+			{Name: "2016 AUG", Y: 0, StringY: "0"},
+			{Name: "2016 SEP", Y: -1.25, StringY: "-1.25"},
+		}
+
+		actual := computeChange(series)
+
+		So(actual.Direction, ShouldEqual, directionDown)
+		So(actual.Absolute, ShouldEqual, -1.25)
+		So(actual.StringAbsolute, ShouldEqual, "1.25")
+		So(actual.Percentage, ShouldBeNil)
+	})
+
+	Convey("Should report no change for equal values.", t, func() {
+		series := []homepage.SparklineData{
+			{Name: "2016 AUG", Y: 4.9, StringY: "4.9"},
+			{Name: "2016 SEP", Y: 4.9, StringY: "4.9"},
+		}
+
+		So(computeChange(series).Direction, ShouldEqual, directionSame)
+	})
+}
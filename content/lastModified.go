@@ -0,0 +1,45 @@
+package content
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// releaseDateField is the field holding the release date of the page and each of its resolved components.
+const releaseDateField = "releaseDate"
+
+// lastModified returns the newest release date in the resolved page data, or the zero time if it has none.
+func lastModified(data []byte) time.Time {
+	var page interface{}
+	if json.Unmarshal(data, &page) != nil {
+		return time.Time{}
+	}
+	return newestReleaseDate(page)
+}
+
+func newestReleaseDate(value interface{}) time.Time {
+	var newest time.Time
+	newer := func(candidate time.Time) {
+		if candidate.After(newest) {
+			newest = candidate
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if releaseDate, ok := child.(string); ok && key == releaseDateField {
+				if released, err := time.Parse(time.RFC3339, releaseDate); err == nil {
+					newer(released.UTC())
+				}
+				continue
+			}
+			newer(newestReleaseDate(child))
+		}
+	case []interface{}:
+		for _, child := range value {
+			newer(newestReleaseDate(child))
+		}
+	}
+	return newest
+}
@@ -0,0 +1,29 @@
+package content
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLastModified(t *testing.T) {
+
+	Convey("Should return the newest release date of the page and its components.", t, func() {
+		data := []byte(`{
+			"metadata": {"releaseDate": "2016-10-18T08:30:00.000Z"},
+			"data": {"headlineFigures": [
+				{"releaseDate": "2016-11-15T09:30:00.000Z"},
+				{"releaseDate": "not a date"},
+				{"releaseDate": "2016-11-09T09:30:00.000Z"}
+			]}
+		}`)
+
+		So(lastModified(data).Equal(time.Date(2016, 11, 15, 9, 30, 0, 0, time.UTC)), ShouldBeTrue)
+	})
+
+	Convey("Should return the zero time if the page has no release dates.", t, func() {
+		So(lastModified([]byte(`{"uri": "/"}`)).IsZero(), ShouldBeTrue)
+		So(lastModified(nil).IsZero(), ShouldBeTrue)
+	})
+}
@@ -0,0 +1,48 @@
+package metadata
+
+import (
+	"strings"
+
+	"github.com/ONSdigital/dp-content-resolver/model"
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+)
+
+// Defaults holds the site-wide metadata values used when Zebedee does not provide them.
+type Defaults struct {
+	SiteDomain  string
+	Title       string
+	Description string
+	Keywords    []string
+}
+
+// Map converts a Zebedee page description into the resolved metadata for the page at the given uri, falling back to
+// the site defaults for any fields Zebedee left empty.
+func (defaults Defaults) Map(uri string, pageType string, description zebedeeModel.PageDescription) model.Metadata {
+	metadata := model.Metadata{
+		Title:        description.Title,
+		Description:  description.Summary,
+		Keywords:     description.Keywords,
+		CanonicalURI: defaults.CanonicalURI(uri),
+		ReleaseDate:  description.ReleaseDate,
+		PageType:     pageType,
+	}
+
+	if len(metadata.Title) == 0 {
+		metadata.Title = defaults.Title
+	}
+	if len(metadata.Description) == 0 {
+		metadata.Description = defaults.Description
+	}
+	if len(metadata.Keywords) == 0 {
+		metadata.Keywords = defaults.Keywords
+	}
+	return metadata
+}
+
+// CanonicalURI returns the absolute URL of the page at the given uri on the configured site domain.
+func (defaults Defaults) CanonicalURI(uri string) string {
+	if len(uri) == 0 || uri[0] != '/' {
+		uri = "/" + uri
+	}
+	return strings.TrimSuffix(defaults.SiteDomain, "/") + uri
+}
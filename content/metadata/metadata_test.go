@@ -0,0 +1,57 @@
+package metadata
+
+import (
+	"testing"
+
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testDefaults = Defaults{
+	SiteDomain:  "https://www.ons.gov.uk",
+	Title:       "Office for National Statistics",
+	Description: "The UK's largest independent producer of official statistics.",
+	Keywords:    []string{"statistics"},
+}
+
+func TestMap(t *testing.T) {
+
+	Convey("Should map the zebedee description fields when they are provided.", t, func() {
+		description := zebedeeModel.PageDescription{
+			Title:       "Inflation and price indices",
+			Summary:     "Prices of goods and services.",
+			Keywords:    []string{"cpi", "inflation"},
+			ReleaseDate: "2016-11-15T00:00:00.000Z",
+		}
+
+		actual := testDefaults.Map("/economy/inflationandpriceindices", "taxonomy_landing_page", description)
+
+		So(actual.Title, ShouldEqual, description.Title)
+		So(actual.Description, ShouldEqual, description.Summary)
+		So(actual.Keywords, ShouldResemble, description.Keywords)
+		So(actual.ReleaseDate, ShouldEqual, description.ReleaseDate)
+		So(actual.PageType, ShouldEqual, "taxonomy_landing_page")
+		So(actual.CanonicalURI, ShouldEqual, "https://www.ons.gov.uk/economy/inflationandpriceindices")
+	})
+
+	Convey("Should use the site defaults for empty zebedee description fields.", t, func() {
+		actual := testDefaults.Map("/", "home_page", zebedeeModel.PageDescription{})
+
+		So(actual.Title, ShouldEqual, testDefaults.Title)
+		So(actual.Description, ShouldEqual, testDefaults.Description)
+		So(actual.Keywords, ShouldResemble, testDefaults.Keywords)
+		So(actual.ReleaseDate, ShouldBeEmpty)
+		So(actual.CanonicalURI, ShouldEqual, "https://www.ons.gov.uk/")
+	})
+}
+
+func TestCanonicalURI(t *testing.T) {
+
+	Convey("Should join the site domain and uri without duplicate slashes.", t, func() {
+		defaults := Defaults{SiteDomain: "https://cy.ons.gov.uk/"}
+
+		So(defaults.CanonicalURI("/economy"), ShouldEqual, "https://cy.ons.gov.uk/economy")
+		So(defaults.CanonicalURI("economy"), ShouldEqual, "https://cy.ons.gov.uk/economy")
+		So(defaults.CanonicalURI(""), ShouldEqual, "https://cy.ons.gov.uk/")
+	})
+}
@@ -0,0 +1,24 @@
+package content
+
+import (
+	"errors"
+
+	"github.com/ONSdigital/dp-content-resolver/model"
+)
+
+// ErrMandatoryComponent is returned when a mandatory component of a page fails to resolve.
+var ErrMandatoryComponent = errors.New("mandatory component failed to resolve")
+
+// mandatoryFailures returns the warnings for the mandatory components of a page.
+func mandatoryFailures(mandatoryComponents []string, warnings []model.Warning) []model.Warning {
+	var failures []model.Warning
+	for _, warning := range warnings {
+		for _, component := range mandatoryComponents {
+			if warning.Component == component {
+				failures = append(failures, warning)
+				break
+			}
+		}
+	}
+	return failures
+}
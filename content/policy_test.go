@@ -0,0 +1,20 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-content-resolver/model"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMandatoryFailures(t *testing.T) {
+	warnings := []model.Warning{
+		{Component: "headline", ResolveURI: "/economy/cpi"},
+		{Component: "taxonomy", ResolveURI: "/"},
+	}
+
+	Convey("Should return only the warnings for mandatory components.", t, func() {
+		So(mandatoryFailures([]string{"taxonomy"}, warnings), ShouldResemble, warnings[1:])
+		So(mandatoryFailures(nil, warnings), ShouldBeEmpty)
+	})
+}
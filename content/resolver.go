@@ -2,37 +2,127 @@ package content
 
 import (
 	"encoding/json"
+	"github.com/ONSdigital/dp-content-resolver/content/fields"
 	"github.com/ONSdigital/dp-content-resolver/content/homePage"
+	"github.com/ONSdigital/dp-content-resolver/metrics"
+	"github.com/ONSdigital/dp-content-resolver/model"
 	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/tracing"
 	"github.com/ONSdigital/dp-content-resolver/zebedee"
 	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
 	"github.com/ONSdigital/go-ns/common"
 	"net/http"
+	"time"
 )
 
-var pageTypeToResolver = map[string]func(*http.Request, zebedeeModel.HomePage, requests.ContextIDGenerator) ([]byte, error){
-	zebedee.HomePage: homePage.Resolve,
+// Resolve statuses recorded in the resolve duration metric.
+const (
+	statusSuccess     = "success"
+	statusDegraded    = "degraded"
+	statusError       = "error"
+	statusUnsupported = "unsupported"
+)
+
+var resolveDuration = metrics.NewHistogram("resolve_duration_seconds",
+	"Duration of page resolves by page type and status.", metrics.DefaultBuckets, "page_type", "status")
+
+var resolvesInFlight = metrics.NewGauge("resolves_in_flight", "Number of page resolves in progress.")
+
+// pageTypeToFields lists the fields that may be requested for each page type.
+var pageTypeToFields = map[string][]string{
+	zebedee.HomePage: homePage.Fields,
+}
+
+// SupportedFields returns the fields that may be requested for each page type using the fields query parameter.
+func SupportedFields() map[string][]string {
+	return pageTypeToFields
+}
+
+// ResolveFunc resolves the page requested.
+type ResolveFunc func(req *http.Request) (*Resolved, *common.ONSError)
+
+// pageResolveFunc resolves the Zebedee content of a page of a supported type.
+type pageResolveFunc func(*http.Request, zebedeeModel.HomePage, *requests.ContextIDGenerator) ([]byte, []model.Warning, error)
+
+// Resolver resolves pages using the Zebedee service it was created with.
+type Resolver struct {
+	zebedeeService      zebedee.Service
+	pageTypeToResolver  map[string]pageResolveFunc
+	mandatoryComponents map[string][]string
+}
+
+// NewResolver creates a resolver requesting content from the Zebedee service. Homepages are resolved by the homepage
+// resolver. mandatoryComponents lists the components of each page type that must resolve. The whole resolve fails if
+// any mandatory component fails, rather than the page being returned with a warning.
+func NewResolver(zebedeeService zebedee.Service, homePageResolver *homePage.Resolver, mandatoryComponents map[string][]string) *Resolver {
+	return &Resolver{
+		zebedeeService: zebedeeService,
+		pageTypeToResolver: map[string]pageResolveFunc{
+			zebedee.HomePage: homePageResolver.Resolve,
+		},
+		mandatoryComponents: mandatoryComponents,
+	}
 }
 
-// ZebedeeService service for communicating with zebedee API.
-var ZebedeeService zebedee.Service
+// Resolved is a resolved page along with a warning for each of its components that failed to resolve.
+type Resolved struct {
+	Data     []byte
+	PageType string
+	Warnings []model.Warning
+	// LastModified is the newest release date of the page and its resolved components, or the zero time if none have
+	// a release date.
+	LastModified time.Time
+}
+
+// Degraded returns true if any components of the page failed to resolve.
+func (resolved *Resolved) Degraded() bool {
+	return len(resolved.Warnings) > 0
+}
 
-// Resolve will take a URL and return a resolved version of the data.
-func Resolve(req *http.Request) ([]byte, *common.ONSError) {
+// Resolve will take a URL and return a resolved version of the data. Pages of unsupported types are returned without
+// data.
+func (resolver *Resolver) Resolve(req *http.Request) (*Resolved, *common.ONSError) {
 	uri := req.URL.Path
 
+	resolvesInFlight.Inc()
+	defer resolvesInFlight.Dec()
+
+	start := time.Now()
+	pageType := "unknown"
+	status := statusError
+	defer func() {
+		resolveDuration.Observe(time.Since(start).Seconds(), pageType, status)
+	}()
+
+	ctx, span := tracing.StartSpan(req.Context(), "content.resolve", tracing.KindInternal)
+	span.SetAttribute("uri", uri)
+	defer func() {
+		span.SetAttribute("page_type", pageType)
+		span.SetAttribute("status", status)
+		span.Finish()
+	}()
+	req = req.WithContext(ctx)
+
 	reqContextIDGen := requests.NewContentIDGenerator(req)
 
-	zebedeeData, pageType, err := ZebedeeService.GetData(uri, reqContextIDGen.Generate())
+	zebedeeData, zebedeePageType, err := resolver.zebedeeService.GetData(ctx, uri, reqContextIDGen.Generate())
 	if err != nil {
+		span.SetError(err)
 		return nil, err
 	}
+	pageType = zebedeePageType
 
 	// look up the resolver function from the pre-populated map.
-	resolveFunc := pageTypeToResolver[pageType]
+	resolveFunc := resolver.pageTypeToResolver[pageType]
 
 	if resolveFunc == nil {
-		return nil, nil
+		status = statusUnsupported
+		return &Resolved{PageType: pageType}, nil
+	}
+
+	if fieldsErr := fields.FromRequest(req).Validate(pageTypeToFields[pageType]); fieldsErr != nil {
+		span.SetError(fieldsErr)
+		return nil, common.NewONSError(fieldsErr, "Invalid fields requested.")
 	}
 
 	var pageToResolve zebedeeModel.HomePage // zebedee model
@@ -42,9 +132,25 @@ func Resolve(req *http.Request) ([]byte, *common.ONSError) {
 		pageToResolve.URI = "/"
 	}
 
-	resolvedData, error := resolveFunc(req, pageToResolve, reqContextIDGen)
-	if err != nil {
+	resolvedData, warnings, error := resolveFunc(req, pageToResolve, reqContextIDGen)
+	if error != nil {
+		span.SetError(error)
 		return nil, common.NewONSError(error, "Resolve error...")
 	}
-	return resolvedData, nil
+
+	if failures := mandatoryFailures(resolver.mandatoryComponents[pageType], warnings); len(failures) > 0 {
+		onsErr := common.NewONSError(ErrMandatoryComponent, "Resolve error...")
+		onsErr.AddParameter("component", failures[0].Component)
+		onsErr.AddParameter("resolveURI", failures[0].ResolveURI)
+		onsErr.AddParameter("failures", failures)
+		span.SetError(onsErr)
+		return nil, onsErr
+	}
+
+	status = statusSuccess
+	if len(warnings) > 0 {
+		status = statusDegraded
+		span.SetAttribute("warnings", len(warnings))
+	}
+	return &Resolved{Data: resolvedData, PageType: pageType, Warnings: warnings, LastModified: lastModified(resolvedData)}, nil
 }
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ONSdigital/dp-content-resolver/config"
+	"github.com/ONSdigital/dp-content-resolver/diff"
+	"github.com/ONSdigital/dp-content-resolver/export"
+)
+
+const diffUsage = "usage: dp-content-resolver diff [flags] [uri...]"
+
+// runDiff resolves the uris given after the flags in args against the configured Zebedee environment and the one it is
+// compared against, or every page reachable from the homepage if no uris are given, and writes a report of the
+// differences between the resolved pages to stdout. It returns the exit code, which is 1 if any page differs.
+func runDiff(args []string, stdout io.Writer, stderr io.Writer) int {
+	logTo(stderr)
+
+	cfg, err := config.Load(args)
+	if err == flag.ErrHelp {
+		fmt.Fprintln(stderr, diffUsage)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	diffCfg := cfg.DiffEnvironment()
+	if diffCfg == nil {
+		fmt.Fprintln(stderr, "one of diff zebedee url, diff content dir or diff replay dir must be set")
+		return 2
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer cancel()
+
+	var snapshots []diff.Snapshot
+	for _, environmentCfg := range []*config.Config{cfg, diffCfg} {
+		env, err := newEnvironment(environmentCfg)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+
+		snapshot, err := diff.Take(ctx, env.zebedeeService, env.content.Resolve, cfg.Args, export.Options{Concurrency: cfg.ExportConcurrency, MaxPages: cfg.ExportMaxPages})
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	report := snapshots[0].Compare(snapshots[1], cfg.DiffIgnore)
+
+	output, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Fprintln(stdout, string(output))
+	fmt.Fprintf(stderr, "Compared %d pages: %d identical, %d different.\n", len(report.Pages), report.Identical, report.Different)
+
+	if report.Different > 0 {
+		return 1
+	}
+	return 0
+}
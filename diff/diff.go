@@ -0,0 +1,128 @@
+// Package diff compares the pages resolved from two Zebedee environments, e.g. before switching Zebedee versions or
+// promoting content.
+package diff
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of difference between two JSON documents.
+const (
+	KindAdded   = "added"
+	KindRemoved = "removed"
+	KindChanged = "changed"
+)
+
+// Difference is a value that differs between two JSON documents, at the dot separated path of the value, e.g.
+// data.headlineFigures.0.title.
+type Difference struct {
+	Path string      `json:"path"`
+	Kind string      `json:"kind"`
+	A    interface{} `json:"a,omitempty"`
+	B    interface{} `json:"b,omitempty"`
+}
+
+// Compare returns the differences between the decoded JSON documents a and b, ordered by path. Values at paths matching
+// any of the ignore patterns are not compared. A pattern is a dot separated path in which * matches any single key or
+// array index, e.g. data.headlineFigures.*.releaseDate. A pattern without a dot matches the key at any depth.
+func Compare(a interface{}, b interface{}, ignore []string) []Difference {
+	differences := compare(nil, a, b, ignore)
+	sort.SliceStable(differences, func(i, j int) bool { return differences[i].Path < differences[j].Path })
+	return differences
+}
+
+func compare(path []string, a interface{}, b interface{}, ignore []string) []Difference {
+	if ignored(path, ignore) {
+		return nil
+	}
+
+	switch aValue := a.(type) {
+	case map[string]interface{}:
+		if bValue, ok := b.(map[string]interface{}); ok {
+			return compareObjects(path, aValue, bValue, ignore)
+		}
+	case []interface{}:
+		if bValue, ok := b.([]interface{}); ok {
+			return compareArrays(path, aValue, bValue, ignore)
+		}
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+	return []Difference{{Path: strings.Join(path, "."), Kind: KindChanged, A: a, B: b}}
+}
+
+func compareObjects(path []string, a map[string]interface{}, b map[string]interface{}, ignore []string) []Difference {
+	var differences []Difference
+	for key, aValue := range a {
+		keyPath := append(path[:len(path):len(path)], key)
+		if bValue, ok := b[key]; ok {
+			differences = append(differences, compare(keyPath, aValue, bValue, ignore)...)
+		} else if !ignored(keyPath, ignore) {
+			differences = append(differences, Difference{Path: strings.Join(keyPath, "."), Kind: KindRemoved, A: aValue})
+		}
+	}
+	for key, bValue := range b {
+		keyPath := append(path[:len(path):len(path)], key)
+		if _, ok := a[key]; !ok && !ignored(keyPath, ignore) {
+			differences = append(differences, Difference{Path: strings.Join(keyPath, "."), Kind: KindAdded, B: bValue})
+		}
+	}
+	return differences
+}
+
+func compareArrays(path []string, a []interface{}, b []interface{}, ignore []string) []Difference {
+	var differences []Difference
+	for i := 0; i < len(a) || i < len(b); i++ {
+		indexPath := append(path[:len(path):len(path)], strconv.Itoa(i))
+		switch {
+		case i >= len(b):
+			if !ignored(indexPath, ignore) {
+				differences = append(differences, Difference{Path: strings.Join(indexPath, "."), Kind: KindRemoved, A: a[i]})
+			}
+		case i >= len(a):
+			if !ignored(indexPath, ignore) {
+				differences = append(differences, Difference{Path: strings.Join(indexPath, "."), Kind: KindAdded, B: b[i]})
+			}
+		default:
+			differences = append(differences, compare(indexPath, a[i], b[i], ignore)...)
+		}
+	}
+	return differences
+}
+
+// ignored returns true if the path matches any of the ignore patterns.
+func ignored(path []string, ignore []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	for _, pattern := range ignore {
+		if !strings.Contains(pattern, ".") {
+			if pattern == "*" || pattern == path[len(path)-1] {
+				return true
+			}
+			continue
+		}
+
+		segments := strings.Split(pattern, ".")
+		if len(segments) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range segments {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func decoded(data string) interface{} {
+	var value interface{}
+	json.Unmarshal([]byte(data), &value)
+	return value
+}
+
+func TestCompare(t *testing.T) {
+
+	Convey("Should report no differences between identical documents.", t, func() {
+		a := decoded(`{"uri": "/", "data": {"headlineFigures": [{"title": "CPI"}]}}`)
+		b := decoded(`{"data": {"headlineFigures": [{"title": "CPI"}]}, "uri": "/"}`)
+
+		So(Compare(a, b, nil), ShouldBeEmpty)
+	})
+
+	Convey("Should report added, removed and changed values by path.", t, func() {
+		a := decoded(`{"uri": "/", "metadata": {"title": "Home"}, "data": {"headlineFigures": [{"title": "CPI"}, {"title": "GDP"}]}}`)
+		b := decoded(`{"uri": "/", "metadata": {"title": "Home page", "description": "ONS"}, "data": {"headlineFigures": [{"title": "CPIH"}]}}`)
+
+		So(Compare(a, b, nil), ShouldResemble, []Difference{
+			{Path: "data.headlineFigures.0.title", Kind: KindChanged, A: "CPI", B: "CPIH"},
+			{Path: "data.headlineFigures.1", Kind: KindRemoved, A: map[string]interface{}{"title": "GDP"}},
+			{Path: "metadata.description", Kind: KindAdded, B: "ONS"},
+			{Path: "metadata.title", Kind: KindChanged, A: "Home", B: "Home page"},
+		})
+	})
+
+	Convey("Should report a value that changes type as changed.", t, func() {
+		So(Compare(decoded(`{"breadcrumb": null}`), decoded(`{"breadcrumb": []}`), nil), ShouldResemble, []Difference{
+			{Path: "breadcrumb", Kind: KindChanged, A: nil, B: []interface{}{}},
+		})
+	})
+
+	Convey("Should not compare ignored fields.", t, func() {
+		a := decoded(`{"metadata": {"releaseDate": "2016"}, "data": {"headlineFigures": [{"releaseDate": "2016", "svg": "<svg/>"}]}}`)
+		b := decoded(`{"metadata": {"releaseDate": "2017"}, "data": {"headlineFigures": [{"releaseDate": "2017"}]}}`)
+
+		So(Compare(a, b, []string{"releaseDate", "data.headlineFigures.*.svg"}), ShouldBeEmpty)
+		So(Compare(a, b, []string{"metadata.releaseDate"}), ShouldHaveLength, 2)
+	})
+}
+
+func TestSnapshotCompare(t *testing.T) {
+
+	Convey("Should report each page of either snapshot ordered by uri.", t, func() {
+		a := Snapshot{
+			"/":        {Status: "resolved", Data: []byte(`{"uri": "/", "title": "Home"}`)},
+			"/economy": {Status: "resolved", Data: []byte(`{"uri": "/economy"}`)},
+			"/aboutus": {Status: "unsupported"},
+		}
+		b := Snapshot{
+			"/":         {Status: "resolved", Data: []byte(`{"uri": "/", "title": "Home page"}`)},
+			"/economy":  {Status: "resolved", Data: []byte(`{"uri": "/economy"}`)},
+			"/business": {Status: "error", Error: "content not found"},
+		}
+
+		report := a.Compare(b, nil)
+
+		So(report.Identical, ShouldEqual, 1)
+		So(report.Different, ShouldEqual, 3)
+		So(report.Pages, ShouldResemble, []PageReport{
+			{URI: "/", StatusA: "resolved", StatusB: "resolved", Differences: []Difference{
+				{Path: "title", Kind: KindChanged, A: "Home", B: "Home page"},
+			}},
+			{URI: "/aboutus", StatusA: "unsupported", StatusB: StatusMissing},
+			{URI: "/business", StatusA: StatusMissing, StatusB: "error", ErrorB: "content not found"},
+			{URI: "/economy", Identical: true, StatusA: "resolved", StatusB: "resolved"},
+		})
+	})
+}
@@ -0,0 +1,163 @@
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ONSdigital/dp-content-resolver/content"
+	"github.com/ONSdigital/dp-content-resolver/export"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+)
+
+// StatusMissing is the status of a page that was not found when crawling one of the environments.
+const StatusMissing = "missing"
+
+// Page is a page resolved from an environment. Status is one of the export page statuses.
+type Page struct {
+	Status string
+	Error  string
+	Data   []byte
+}
+
+// Snapshot holds the pages resolved from an environment, keyed by uri.
+type Snapshot map[string]Page
+
+// Report describes the differences between the pages resolved from environments a and b.
+type Report struct {
+	Pages     []PageReport `json:"pages"`
+	Identical int          `json:"identical"`
+	Different int          `json:"different"`
+}
+
+// PageReport describes the differences between a page resolved from environments a and b.
+type PageReport struct {
+	URI         string       `json:"uri"`
+	Identical   bool         `json:"identical"`
+	StatusA     string       `json:"statusA"`
+	StatusB     string       `json:"statusB"`
+	ErrorA      string       `json:"errorA,omitempty"`
+	ErrorB      string       `json:"errorB,omitempty"`
+	Differences []Difference `json:"differences,omitempty"`
+}
+
+// Take resolves the pages at the uris from the environment of the Zebedee service and resolve function. If no uris
+// are given, every page reachable from the homepage is crawled instead, using the export options.
+func Take(ctx context.Context, zebedeeService zebedee.Service, resolve content.ResolveFunc, uris []string, options export.Options) (Snapshot, error) {
+	if len(uris) == 0 {
+		return crawl(ctx, zebedeeService, resolve, options)
+	}
+
+	reqContextIDGen := requests.NewContextIDGenerator("diff-" + requests.NewRequestID(8))
+	snapshot := make(Snapshot)
+	for _, uri := range uris {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequest("GET", uri, nil)
+		if err != nil {
+			snapshot[uri] = Page{Status: export.StatusError, Error: err.Error()}
+			continue
+		}
+		req = req.WithContext(zebedee.WithSharedResponses(ctx))
+		req.Header.Set(requests.RequestIDHeaderParam, reqContextIDGen.Generate())
+
+		resolved, onsErr := resolve(req)
+		switch {
+		case onsErr != nil:
+			snapshot[uri] = Page{Status: export.StatusError, Error: onsErr.Error()}
+		case resolved.Data == nil:
+			snapshot[uri] = Page{Status: export.StatusUnsupported}
+		case resolved.Degraded():
+			snapshot[uri] = Page{Status: export.StatusDegraded, Data: resolved.Data}
+		default:
+			snapshot[uri] = Page{Status: export.StatusResolved, Data: resolved.Data}
+		}
+	}
+	return snapshot, nil
+}
+
+// crawl exports the site to a temporary directory and reads back every page exported.
+func crawl(ctx context.Context, zebedeeService zebedee.Service, resolve content.ResolveFunc, options export.Options) (Snapshot, error) {
+	dir, err := ioutil.TempDir("", "dp-content-resolver-diff")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	manifest, err := export.Crawl(ctx, zebedeeService, resolve, dir, options)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := make(Snapshot)
+	for _, page := range manifest.Pages {
+		snapshotPage := Page{Status: page.Status, Error: page.Error}
+		if len(page.File) > 0 {
+			if snapshotPage.Data, err = ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(page.File))); err != nil {
+				return nil, err
+			}
+		}
+		snapshot[page.URI] = snapshotPage
+	}
+	return snapshot, nil
+}
+
+// Compare returns the report of the differences between the pages of snapshots a and b, ordered by uri. Fields at
+// paths matching any of the ignore patterns are not compared.
+func (a Snapshot) Compare(b Snapshot, ignore []string) *Report {
+	uris := make(map[string]bool)
+	for uri := range a {
+		uris[uri] = true
+	}
+	for uri := range b {
+		uris[uri] = true
+	}
+
+	report := &Report{Pages: []PageReport{}}
+	for uri := range uris {
+		pageReport := comparePages(uri, a, b, ignore)
+		if pageReport.Identical {
+			report.Identical++
+		} else {
+			report.Different++
+		}
+		report.Pages = append(report.Pages, pageReport)
+	}
+	sort.Slice(report.Pages, func(i, j int) bool { return report.Pages[i].URI < report.Pages[j].URI })
+	return report
+}
+
+func comparePages(uri string, a Snapshot, b Snapshot, ignore []string) PageReport {
+	pageA, ok := a[uri]
+	if !ok {
+		pageA.Status = StatusMissing
+	}
+	pageB, ok := b[uri]
+	if !ok {
+		pageB.Status = StatusMissing
+	}
+
+	report := PageReport{URI: uri, StatusA: pageA.Status, StatusB: pageB.Status, ErrorA: pageA.Error, ErrorB: pageB.Error}
+	// a page that was only resolved in one environment is reported by its status rather than every field it holds.
+	if pageA.Data != nil && pageB.Data != nil {
+		report.Differences = Compare(decode(pageA.Data), decode(pageB.Data), ignore)
+	}
+	report.Identical = report.StatusA == report.StatusB && len(report.Differences) == 0
+	return report
+}
+
+// decode returns the decoded JSON page data, or nil if there is none.
+func decode(data []byte) interface{} {
+	var value interface{}
+	if len(data) > 0 {
+		json.Unmarshal(data, &value)
+	}
+	return value
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-content-resolver/diff"
+	"github.com/ONSdigital/go-ns/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// copyContent copies the test content to a temporary directory, replacing old with new in every file.
+func copyContent(t *testing.T, old string, new string) string {
+	dir, err := ioutil.TempDir("", "dp-content-resolver-diff-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := "zebedee/testdata/content"
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, strings.TrimPrefix(path, root))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(target, []byte(strings.Replace(string(data), old, new, -1)), 0644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRunDiff(t *testing.T) {
+	defer func(event func(string, string, log.Data)) { log.Event = event }(log.Event)
+
+	runDiffReport := func(args ...string) (int, diff.Report, string) {
+		var stdout, stderr bytes.Buffer
+		code := runDiff(args, &stdout, &stderr)
+
+		var report diff.Report
+		json.Unmarshal(stdout.Bytes(), &report)
+		return code, report, stderr.String()
+	}
+
+	changed := copyContent(t, "Inflation and price indices", "Prices")
+	defer os.RemoveAll(changed)
+
+	Convey("Should report the pages resolved from each environment as identical.", t, func() {
+		code, report, stderr := runDiffReport("-zebedee-content-dir", "zebedee/testdata/content", "-diff-content-dir", "zebedee/testdata/content", "/", "/economy")
+
+		So(code, ShouldEqual, 0)
+		So(report.Identical, ShouldEqual, 2)
+		So(stderr, ShouldContainSubstring, "Compared 2 pages: 2 identical, 0 different.")
+	})
+
+	Convey("Should report the fields that differ when crawling each environment.", t, func() {
+		code, report, _ := runDiffReport("-zebedee-content-dir", "zebedee/testdata/content", "-diff-content-dir", changed)
+
+		So(code, ShouldEqual, 1)
+		So(report.Different, ShouldBeGreaterThan, 0)
+
+		var home diff.PageReport
+		for _, page := range report.Pages {
+			if page.URI == "/" {
+				home = page
+			}
+		}
+		So(home.Identical, ShouldBeFalse)
+		So(home.Differences, ShouldContain, diff.Difference{
+			Path: "taxonomy.0.children.1.title", Kind: diff.KindChanged, A: "Inflation and price indices", B: "Prices",
+		})
+	})
+
+	Convey("Should ignore the configured fields.", t, func() {
+		code, report, _ := runDiffReport("-zebedee-content-dir", "zebedee/testdata/content", "-diff-content-dir", changed,
+			"-diff-ignore", "title", "/")
+
+		So(code, ShouldEqual, 0)
+		So(report.Identical, ShouldEqual, 1)
+	})
+
+	Convey("Should require an environment to compare against.", t, func() {
+		code, _, stderr := runDiffReport("/")
+
+		So(code, ShouldEqual, 2)
+		So(stderr, ShouldContainSubstring, "must be set")
+	})
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ONSdigital/dp-content-resolver/config"
+	"github.com/ONSdigital/dp-content-resolver/export"
+)
+
+const exportUsage = "usage: dp-content-resolver export [flags] <output dir>"
+
+// runExport crawls the site from the homepage, writing every resolved page and a manifest to the output directory
+// given after the flags in args. It writes a summary of the pages exported to stdout and returns the exit code, which
+// is non zero if the export could not be completed.
+func runExport(args []string, stdout io.Writer, stderr io.Writer) int {
+	logTo(stderr)
+
+	cfg, err := config.Load(args)
+	if err == flag.ErrHelp {
+		fmt.Fprintln(stderr, exportUsage)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if len(cfg.Args) != 1 {
+		fmt.Fprintln(stderr, exportUsage)
+		return 2
+	}
+
+	env, err := newEnvironment(cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer cancel()
+
+	manifest, err := export.Crawl(ctx, env.zebedeeService, env.content.Resolve, cfg.Args[0], export.Options{Concurrency: cfg.ExportConcurrency, MaxPages: cfg.ExportMaxPages})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	counts := make(map[string]int)
+	for _, page := range manifest.Pages {
+		counts[page.Status]++
+	}
+	fmt.Fprintf(stdout, "Exported %d pages to %s: %d resolved, %d degraded, %d unsupported, %d errors.\n",
+		len(manifest.Pages), cfg.Args[0], counts[export.StatusResolved], counts[export.StatusDegraded],
+		counts[export.StatusUnsupported], counts[export.StatusError])
+	return 0
+}
@@ -0,0 +1,225 @@
+// Package export crawls the site from the homepage, resolving every page reachable from it and writing the resolved
+// pages to a directory, e.g. to prerender the site or keep an offline snapshot of it.
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/content"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// Statuses of the pages in the manifest.
+const (
+	StatusResolved    = "resolved"
+	StatusDegraded    = "degraded"
+	StatusUnsupported = "unsupported"
+	StatusError       = "error"
+)
+
+// ManifestFile is the name of the manifest written to the output directory.
+const ManifestFile = "manifest.json"
+
+// pageFile is the name of the file each resolved page is written to, in the directory at its uri.
+const pageFile = "data.json"
+
+// Options limits the crawl.
+type Options struct {
+	// Concurrency is the maximum number of pages resolved at the same time.
+	Concurrency int
+	// MaxPages is the maximum number of pages crawled.
+	MaxPages int
+}
+
+// Manifest lists every page crawled.
+type Manifest struct {
+	Created time.Time `json:"created"`
+	Pages   []Page    `json:"pages"`
+}
+
+// Page describes a page crawled and the file its resolved page was written to, if it was resolved.
+type Page struct {
+	URI      string `json:"uri"`
+	PageType string `json:"pageType,omitempty"`
+	Status   string `json:"status"`
+	File     string `json:"file,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// crawler holds the state of a crawl.
+type crawler struct {
+	ctx             context.Context
+	zebedeeService  zebedee.Service
+	resolve         content.ResolveFunc
+	dir             string
+	options         Options
+	reqContextIDGen *requests.ContextIDGenerator
+
+	mutex sync.Mutex
+	seen  map[string]bool
+	pages []Page
+	pool  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// Crawl resolves every page reachable from the homepage, through the taxonomy and the links in the content of each
+// page, writing each resolved page to the directory in the same layout as the Zebedee content, e.g.
+// economy/data.json, along with a manifest of every page crawled. The Zebedee service is used to find the pages
+// linked to from each page.
+func Crawl(ctx context.Context, zebedeeService zebedee.Service, resolve content.ResolveFunc, dir string, options Options) (*Manifest, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = 1
+	}
+
+	c := &crawler{
+		ctx:             ctx,
+		zebedeeService:  zebedeeService,
+		resolve:         resolve,
+		dir:             dir,
+		options:         options,
+		reqContextIDGen: requests.NewContextIDGenerator("export-" + requests.NewRequestID(8)),
+		seen:            make(map[string]bool),
+		pool:            make(chan struct{}, options.Concurrency),
+	}
+
+	c.visit("/")
+	c.wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(c.pages, func(i, j int) bool { return c.pages[i].URI < c.pages[j].URI })
+	manifest := &Manifest{Created: time.Now().UTC(), Pages: c.pages}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return manifest, ioutil.WriteFile(filepath.Join(dir, ManifestFile), data, 0644)
+}
+
+// visit crawls the page at the uri if it has not already been crawled.
+func (c *crawler) visit(uri string) {
+	c.mutex.Lock()
+	if c.seen[uri] || len(c.seen) >= c.options.MaxPages || c.ctx.Err() != nil {
+		c.mutex.Unlock()
+		return
+	}
+	c.seen[uri] = true
+	c.mutex.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		c.pool <- struct{}{}
+		page, links := c.export(uri)
+		<-c.pool
+
+		c.mutex.Lock()
+		c.pages = append(c.pages, page)
+		c.mutex.Unlock()
+
+		for _, link := range links {
+			c.visit(link)
+		}
+	}()
+}
+
+// export resolves and writes the page at the uri, returning it along with the uris of the pages it links to.
+func (c *crawler) export(uri string) (Page, []string) {
+	page := Page{URI: uri}
+	reqContextIDGen := c.reqContextIDGen.Child()
+
+	// the page data is requested by both the link search and the resolver, so share it between them.
+	ctx := zebedee.WithSharedResponses(c.ctx)
+	links := c.links(ctx, uri, reqContextIDGen)
+
+	req, _ := http.NewRequest("GET", uri, nil)
+	req = req.WithContext(ctx)
+	req.Header.Set(requests.RequestIDHeaderParam, reqContextIDGen.Generate())
+
+	resolved, err := c.resolve(req)
+	if err != nil {
+		log.Error(err, log.Data{"description": "Failed to export page.", "uri": uri})
+		page.Status, page.Error = StatusError, err.Error()
+		return page, links
+	}
+
+	page.PageType = resolved.PageType
+	if resolved.Data == nil {
+		page.Status = StatusUnsupported
+		return page, links
+	}
+
+	page.Status = StatusResolved
+	if resolved.Degraded() {
+		page.Status = StatusDegraded
+	}
+
+	page.File = path.Join(strings.TrimPrefix(uri, "/"), pageFile)
+	if writeErr := c.write(page.File, resolved.Data); writeErr != nil {
+		log.Error(writeErr, log.Data{"description": "Failed to write exported page.", "uri": uri})
+		page.Status, page.Error, page.File = StatusError, writeErr.Error(), ""
+		return page, links
+	}
+
+	hash := sha256.Sum256(resolved.Data)
+	page.Hash = hex.EncodeToString(hash[:])
+	return page, links
+}
+
+// links returns the uris of the taxonomy beneath the page and of the pages linked to in its content.
+func (c *crawler) links(ctx context.Context, uri string, reqContextIDGen *requests.ContextIDGenerator) []string {
+	var links []string
+
+	taxonomy, err := c.zebedeeService.GetTaxonomy(ctx, uri, 1, reqContextIDGen.Generate())
+	if err != nil {
+		log.Error(err, log.Data{"description": "Failed to get taxonomy of exported page.", "uri": uri})
+	}
+	for _, node := range taxonomy {
+		links = append(links, node.URI)
+	}
+
+	var pages []string
+	for _, link := range links {
+		if link = zebedeeModel.NormaliseURI(link); len(link) > 0 && link != uri {
+			pages = append(pages, link)
+		}
+	}
+
+	data, _, err := c.zebedeeService.GetData(ctx, uri, reqContextIDGen.Generate())
+	if err == nil {
+		pages = append(pages, zebedeeModel.PageLinks(uri, data)...)
+	}
+	return pages
+}
+
+// write writes the resolved page data to the file within the output directory.
+func (c *crawler) write(file string, data []byte) error {
+	filePath := filepath.Join(c.dir, filepath.FromSlash(file))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, data, 0644)
+}
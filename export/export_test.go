@@ -0,0 +1,71 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ONSdigital/dp-content-resolver/content"
+	"github.com/ONSdigital/dp-content-resolver/content/homePage"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCrawl(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fs := zebedee.NewFileSystem("../zebedee/testdata/content")
+	resolver := content.NewResolver(fs, homePage.NewResolver(fs, homePage.Options{TaxonomyDepth: 2}), nil)
+
+	Convey("Should export every page reachable from the homepage.", t, func() {
+		manifest, err := Crawl(context.Background(), fs, resolver.Resolve, dir, Options{Concurrency: 2, MaxPages: 100})
+		So(err, ShouldBeNil)
+
+		var uris []string
+		for _, page := range manifest.Pages {
+			uris = append(uris, page.URI)
+		}
+		So(uris, ShouldResemble, []string{
+			"/",
+			"/economy",
+			"/economy/grossdomesticproductgdp",
+			"/economy/inflationandpriceindices",
+			"/economy/inflationandpriceindices/articles/shoppingpricecomparisontool/2016-11-15",
+			"/economy/inflationandpriceindices/bulletins/consumerpriceinflation/oct2016",
+			"/economy/inflationandpriceindices/bulletins/consumerpriceinflation/sept2016",
+			"/economy/inflationandpriceindices/datasets/consumerpriceinflation",
+			"/economy/inflationandpriceindices/timeseries/d7g7",
+			"/employmentandlabourmarket",
+		})
+
+		home := manifest.Pages[0]
+		So(home.PageType, ShouldEqual, zebedee.HomePage)
+		So(home.Status, ShouldEqual, StatusResolved)
+		So(home.File, ShouldEqual, "data.json")
+		So(len(home.Hash), ShouldEqual, 64)
+		So(manifest.Pages[1].Status, ShouldEqual, StatusUnsupported)
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, home.File))
+		So(err, ShouldBeNil)
+		So(string(data), ShouldContainSubstring, `"headlineFigures"`)
+
+		var written Manifest
+		data, _ = ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+		So(json.Unmarshal(data, &written), ShouldBeNil)
+		So(len(written.Pages), ShouldEqual, len(manifest.Pages))
+	})
+
+	Convey("Should stop crawling at the maximum number of pages.", t, func() {
+		manifest, err := Crawl(context.Background(), fs, resolver.Resolve, dir, Options{Concurrency: 1, MaxPages: 2})
+
+		So(err, ShouldBeNil)
+		So(len(manifest.Pages), ShouldEqual, 2)
+	})
+}
@@ -0,0 +1,288 @@
+// Package feed finds the most recent publications beneath a page of the taxonomy so that they can be subscribed to as
+// RSS or Atom feeds.
+package feed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/content/metadata"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+	"github.com/ONSdigital/go-ns/common"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// concurrency is the maximum number of pages requested from Zebedee at the same time when finding publications.
+const concurrency = 8
+
+// maxCacheEntries bounds the memory used by the feed cache.
+const maxCacheEntries = 1000
+
+// ErrNotTaxonomyPage is returned for feeds requested for pages that are not part of the taxonomy.
+var ErrNotTaxonomyPage = errors.New("feeds are only available for pages of the taxonomy")
+
+// taxonomyPageTypes are the page types that feeds may be requested for.
+var taxonomyPageTypes = map[string]bool{
+	zebedee.HomePage:            true,
+	zebedee.TaxonomyLandingPage: true,
+	zebedee.ProductPage:         true,
+}
+
+// publicationPageTypes are the page types listed in feeds.
+var publicationPageTypes = map[string]bool{
+	zebedee.Bulletin:           true,
+	zebedee.Article:            true,
+	zebedee.DatasetLandingPage: true,
+}
+
+// Options controls which publications are listed in feeds.
+type Options struct {
+	// MaxItems is the maximum number of publications listed in each feed.
+	MaxItems int
+	// TaxonomyDepth is the depth of the taxonomy beneath the feed page searched for publications.
+	TaxonomyDepth int
+	// MaxLinks is the maximum number of pages linked to from the feed page and the taxonomy beneath it that are read
+	// to find publications.
+	MaxLinks int
+	// CacheTTL is how long a found feed is reused before it is found again. 0 disables caching.
+	CacheTTL time.Duration
+	// Site holds the site-wide title, description and domain of the rendered feeds.
+	Site metadata.Defaults
+}
+
+// Finder finds the publications of feeds using the Zebedee service it was created with.
+type Finder struct {
+	zebedeeService zebedee.Service
+	options        Options
+
+	mutex sync.RWMutex
+	cache map[string]cachedFeed
+}
+
+type cachedFeed struct {
+	feed    *Feed
+	expires time.Time
+}
+
+// NewFinder creates a feed finder requesting content from the Zebedee service.
+func NewFinder(zebedeeService zebedee.Service, options Options) *Finder {
+	return &Finder{zebedeeService: zebedeeService, options: options, cache: make(map[string]cachedFeed)}
+}
+
+// Feed is the page of the taxonomy a feed was requested for and its most recent publications.
+type Feed struct {
+	URI     string
+	Title   string
+	Summary string
+	Updated time.Time
+	Items   []Item
+
+	site metadata.Defaults
+}
+
+// Item is a publication listed in a feed.
+type Item struct {
+	URI      string
+	PageType string
+	Title    string
+	Summary  string
+	Released time.Time
+}
+
+// page is the Zebedee content of a page.
+type page struct {
+	pageType string
+	data     []byte
+}
+
+// description returns the description of the page content.
+func (p page) description() zebedeeModel.PageDescription {
+	var content struct {
+		Description zebedeeModel.PageDescription `json:"description"`
+	}
+	json.Unmarshal(p.data, &content)
+	return content.Description
+}
+
+// Find returns the feed of the most recent bulletins, articles and datasets linked to from the page of the taxonomy at
+// the uri, or from the pages of the taxonomy beneath it, newest first. Feeds are reused for the cache ttl once found.
+// The returned feed must not be modified.
+func (finder *Finder) Find(ctx context.Context, uri string, reqContextIDGen *requests.ContextIDGenerator) (*Feed, *common.ONSError) {
+	if feed, ok := finder.cached(uri); ok {
+		return feed, nil
+	}
+
+	feed, err := finder.find(ctx, uri, reqContextIDGen)
+	if err != nil {
+		return nil, err
+	}
+	finder.store(uri, feed)
+	return feed, nil
+}
+
+// cached returns the feed found for the uri if it has not expired.
+func (finder *Finder) cached(uri string) (*Feed, bool) {
+	finder.mutex.RLock()
+	entry, ok := finder.cache[uri]
+	finder.mutex.RUnlock()
+
+	if !ok || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.feed, true
+}
+
+// store caches the feed found for the uri, unless caching is disabled or the cache is full of unexpired feeds.
+func (finder *Finder) store(uri string, feed *Feed) {
+	if finder.options.CacheTTL <= 0 {
+		return
+	}
+	now := time.Now()
+
+	finder.mutex.Lock()
+	defer finder.mutex.Unlock()
+
+	if len(finder.cache) >= maxCacheEntries {
+		for key, entry := range finder.cache {
+			if now.After(entry.expires) {
+				delete(finder.cache, key)
+			}
+		}
+		if len(finder.cache) >= maxCacheEntries {
+			return
+		}
+	}
+	finder.cache[uri] = cachedFeed{feed: feed, expires: now.Add(finder.options.CacheTTL)}
+}
+
+// find requests the content of the feed from Zebedee.
+func (finder *Finder) find(ctx context.Context, uri string, reqContextIDGen *requests.ContextIDGenerator) (*Feed, *common.ONSError) {
+	ctx = zebedee.WithSharedResponses(ctx)
+
+	data, pageType, err := finder.zebedeeService.GetData(ctx, uri, reqContextIDGen.Generate())
+	if err != nil {
+		return nil, err
+	}
+	if !taxonomyPageTypes[pageType] {
+		onsErr := common.NewONSError(ErrNotTaxonomyPage, "Feed requested for a page that is not part of the taxonomy.")
+		onsErr.AddParameter("uri", uri)
+		onsErr.AddParameter("pageType", pageType)
+		return nil, onsErr
+	}
+
+	description := page{pageType: pageType, data: data}.description()
+	feed := &Feed{URI: uri, Title: description.Title, Summary: description.Summary, Items: []Item{}, site: finder.options.Site}
+
+	taxonomy, err := finder.zebedeeService.GetTaxonomy(ctx, uri, finder.options.TaxonomyDepth, reqContextIDGen.Generate())
+	if err != nil {
+		log.Error(err, log.Data{"description": "Failed to get the taxonomy beneath a feed page.", "uri": uri})
+	}
+
+	nodeURIs := flatten(taxonomy)
+	nodes := finder.getPages(ctx, nodeURIs, reqContextIDGen)
+	links := zebedeeModel.PageLinks(uri, data)
+	for _, nodeURI := range nodeURIs {
+		if node, ok := nodes[nodeURI]; ok {
+			links = append(links, zebedeeModel.PageLinks(nodeURI, node.data)...)
+		}
+	}
+	links = finder.limitLinks(uri, links)
+
+	for publicationURI, publication := range finder.getPages(ctx, links, reqContextIDGen) {
+		if !publicationPageTypes[publication.pageType] {
+			continue
+		}
+		description := publication.description()
+		released, _ := time.Parse(time.RFC3339, description.ReleaseDate)
+		feed.Items = append(feed.Items, Item{
+			URI:      publicationURI,
+			PageType: publication.pageType,
+			Title:    description.Title,
+			Summary:  description.Summary,
+			Released: released.UTC(),
+		})
+	}
+
+	sort.Slice(feed.Items, func(i, j int) bool {
+		if !feed.Items[i].Released.Equal(feed.Items[j].Released) {
+			return feed.Items[i].Released.After(feed.Items[j].Released)
+		}
+		return feed.Items[i].URI < feed.Items[j].URI
+	})
+	if len(feed.Items) > finder.options.MaxItems {
+		feed.Items = feed.Items[:finder.options.MaxItems]
+	}
+	if len(feed.Items) > 0 {
+		feed.Updated = feed.Items[0].Released
+	}
+	return feed, nil
+}
+
+// limitLinks returns the first of the distinct links, up to the maximum number of links, so that the links nearest the
+// feed page are read first.
+func (finder *Finder) limitLinks(uri string, links []string) []string {
+	distinct := make([]string, 0, len(links))
+	seen := make(map[string]bool)
+	for _, link := range links {
+		if !seen[link] {
+			seen[link] = true
+			distinct = append(distinct, link)
+		}
+	}
+
+	if finder.options.MaxLinks > 0 && len(distinct) > finder.options.MaxLinks {
+		log.Debug("Limiting the links read for a feed", log.Data{"uri": uri, "links": len(distinct), "maxLinks": finder.options.MaxLinks})
+		distinct = distinct[:finder.options.MaxLinks]
+	}
+	return distinct
+}
+
+// getPages returns the content of each of the pages at the uris, keyed by uri. Pages that cannot be read are left out.
+func (finder *Finder) getPages(ctx context.Context, uris []string, reqContextIDGen *requests.ContextIDGenerator) map[string]page {
+	pages := make(map[string]page)
+	requested := make(map[string]bool)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	pool := make(chan struct{}, concurrency)
+
+	for _, uri := range uris {
+		if requested[uri] {
+			continue
+		}
+		requested[uri] = true
+
+		wg.Add(1)
+		go func(uri string) {
+			defer wg.Done()
+			pool <- struct{}{}
+			defer func() { <-pool }()
+
+			data, pageType, err := finder.zebedeeService.GetData(ctx, uri, reqContextIDGen.Generate())
+			if err != nil {
+				return
+			}
+			mutex.Lock()
+			pages[uri] = page{pageType: pageType, data: data}
+			mutex.Unlock()
+		}(uri)
+	}
+
+	wg.Wait()
+	return pages
+}
+
+// flatten returns the uri of every node in the taxonomy.
+func flatten(nodes []zebedeeModel.ContentNode) []string {
+	var uris []string
+	for _, node := range nodes {
+		uris = append(uris, node.URI)
+		uris = append(uris, flatten(node.Children)...)
+	}
+	return uris
+}
@@ -0,0 +1,180 @@
+package feed
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/content/metadata"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	"github.com/ONSdigital/go-ns/common"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testContentDir = "../zebedee/testdata/content"
+
+var testOptions = Options{
+	MaxItems:      20,
+	TaxonomyDepth: 2,
+	MaxLinks:      200,
+	Site:          metadata.Defaults{SiteDomain: "https://www.ons.gov.uk", Title: "Office for National Statistics"},
+}
+
+// countingService counts the pages requested from the Zebedee service it wraps.
+type countingService struct {
+	zebedee.Service
+	requests int32
+}
+
+func (service *countingService) GetData(ctx context.Context, uri string, requestContextID string) ([]byte, string, *common.ONSError) {
+	atomic.AddInt32(&service.requests, 1)
+	return service.Service.GetData(ctx, uri, requestContextID)
+}
+
+func TestFind(t *testing.T) {
+	finder := NewFinder(zebedee.NewFileSystem(testContentDir), testOptions)
+	reqContextIDGen := requests.NewContentIDGenerator(httptest.NewRequest("GET", "/feed/rss/economy", nil))
+
+	Convey("Should list the publications beneath the taxonomy page, newest first.", t, func() {
+		feed, err := finder.Find(context.Background(), "/economy", reqContextIDGen)
+		So(err, ShouldBeNil)
+		So(feed.Title, ShouldEqual, "Economy")
+		So(feed.Updated.Equal(time.Date(2016, 11, 15, 9, 30, 0, 0, time.UTC)), ShouldBeTrue)
+
+		var uris []string
+		for _, item := range feed.Items {
+			uris = append(uris, item.URI)
+		}
+		So(uris, ShouldResemble, []string{
+			"/economy/inflationandpriceindices/articles/shoppingpricecomparisontool/2016-11-15",
+			"/economy/inflationandpriceindices/bulletins/consumerpriceinflation/oct2016",
+			"/economy/inflationandpriceindices/datasets/consumerpriceinflation",
+			"/economy/inflationandpriceindices/bulletins/consumerpriceinflation/sept2016",
+		})
+		So(feed.Items[1], ShouldResemble, Item{
+			URI:      "/economy/inflationandpriceindices/bulletins/consumerpriceinflation/oct2016",
+			PageType: zebedee.Bulletin,
+			Title:    "UK consumer price inflation: October 2016",
+			Summary:  "Price indices, percentage changes and weights for the different measures of consumer price inflation.",
+			Released: time.Date(2016, 11, 15, 9, 30, 0, 0, time.UTC),
+		})
+	})
+
+	Convey("Should list at most the maximum number of items.", t, func() {
+		options := testOptions
+		options.MaxItems = 1
+
+		feed, err := NewFinder(zebedee.NewFileSystem(testContentDir), options).Find(context.Background(), "/", reqContextIDGen)
+		So(err, ShouldBeNil)
+		So(len(feed.Items), ShouldEqual, 1)
+	})
+
+	Convey("Should read at most the maximum number of links.", t, func() {
+		options := testOptions
+		options.MaxLinks = 1
+		service := &countingService{Service: zebedee.NewFileSystem(testContentDir)}
+
+		feed, err := NewFinder(service, options).Find(context.Background(), "/economy", reqContextIDGen)
+		So(err, ShouldBeNil)
+		So(len(feed.Items), ShouldBeLessThanOrEqualTo, 1)
+
+		unlimited := &countingService{Service: zebedee.NewFileSystem(testContentDir)}
+		NewFinder(unlimited, testOptions).Find(context.Background(), "/economy", reqContextIDGen)
+		So(service.requests, ShouldBeLessThan, unlimited.requests)
+	})
+
+	Convey("Should reuse feeds until the cache ttl has passed.", t, func() {
+		options := testOptions
+		options.CacheTTL = time.Minute
+		service := &countingService{Service: zebedee.NewFileSystem(testContentDir)}
+		cached := NewFinder(service, options)
+
+		first, err := cached.Find(context.Background(), "/economy", reqContextIDGen)
+		So(err, ShouldBeNil)
+		requests := service.requests
+
+		second, err := cached.Find(context.Background(), "/economy", reqContextIDGen)
+		So(err, ShouldBeNil)
+		So(second, ShouldEqual, first)
+		So(service.requests, ShouldEqual, requests)
+
+		cached.cache["/economy"] = cachedFeed{feed: first, expires: time.Now().Add(-time.Second)}
+		third, _ := cached.Find(context.Background(), "/economy", reqContextIDGen)
+		So(third, ShouldNotEqual, first)
+		So(service.requests, ShouldBeGreaterThan, requests)
+	})
+
+	Convey("Should not cache feeds that are not found.", t, func() {
+		options := testOptions
+		options.CacheTTL = time.Minute
+		cached := NewFinder(zebedee.NewFileSystem(testContentDir), options)
+
+		cached.Find(context.Background(), "/business", reqContextIDGen)
+		So(cached.cache, ShouldBeEmpty)
+	})
+
+	Convey("Should return an empty feed for taxonomy pages without publications.", t, func() {
+		feed, err := finder.Find(context.Background(), "/employmentandlabourmarket", reqContextIDGen)
+		So(err, ShouldBeNil)
+		So(feed.Items, ShouldBeEmpty)
+	})
+
+	Convey("Should only provide feeds for pages of the taxonomy.", t, func() {
+		_, err := finder.Find(context.Background(), "/economy/inflationandpriceindices/timeseries/d7g7", reqContextIDGen)
+		So(err.RootError, ShouldEqual, ErrNotTaxonomyPage)
+
+		_, err = finder.Find(context.Background(), "/business", reqContextIDGen)
+		So(err.RootError, ShouldEqual, zebedee.ErrNotFound)
+	})
+}
+
+func TestRender(t *testing.T) {
+	feed := &Feed{
+		URI:     "/economy",
+		Title:   "Economy",
+		Updated: time.Date(2016, 11, 15, 9, 30, 0, 0, time.UTC),
+		Items: []Item{{
+			URI:      "/economy/inflationandpriceindices/bulletins/consumerpriceinflation/oct2016",
+			PageType: zebedee.Bulletin,
+			Title:    "UK consumer price inflation: October 2016",
+			Summary:  "Consumer price inflation & weights.",
+			Released: time.Date(2016, 11, 15, 9, 30, 0, 0, time.UTC),
+		}},
+		site: testOptions.Site,
+	}
+	link := "https://www.ons.gov.uk/economy/inflationandpriceindices/bulletins/consumerpriceinflation/oct2016"
+
+	Convey("Should render the feed as RSS 2.0 with permalink guids.", t, func() {
+		data, err := feed.RSS("/feed/rss/economy")
+		So(err, ShouldBeNil)
+
+		var parsed rss
+		So(xml.Unmarshal(data, &parsed), ShouldBeNil)
+		So(parsed.Version, ShouldEqual, "2.0")
+		So(parsed.Channel.Title, ShouldEqual, "Office for National Statistics: Economy")
+		So(string(data), ShouldContainSubstring, "<link>https://www.ons.gov.uk/economy</link>")
+		So(parsed.Channel.LastBuildDate, ShouldEqual, "Tue, 15 Nov 2016 09:30:00 +0000")
+		So(parsed.Channel.Items[0].GUID, ShouldResemble, rssGUID{IsPermaLink: true, Value: link})
+		So(parsed.Channel.Items[0].Description, ShouldEqual, "Consumer price inflation & weights.")
+		So(string(data), ShouldContainSubstring, `<atom:link href="https://www.ons.gov.uk/feed/rss/economy" rel="self" type="application/rss+xml"></atom:link>`)
+	})
+
+	Convey("Should render the feed as Atom with the page URL as each entry id.", t, func() {
+		data, err := feed.Atom("/feed/atom/economy")
+		So(err, ShouldBeNil)
+
+		var parsed atomFeed
+		So(xml.Unmarshal(data, &parsed), ShouldBeNil)
+		So(parsed.ID, ShouldEqual, "https://www.ons.gov.uk/economy")
+		So(parsed.Updated, ShouldEqual, "2016-11-15T09:30:00Z")
+		So(parsed.Author.Name, ShouldEqual, "Office for National Statistics")
+		So(parsed.Entries[0].ID, ShouldEqual, link)
+		So(parsed.Entries[0].Published, ShouldEqual, "2016-11-15T09:30:00Z")
+		So(parsed.Entries[0].Category.Term, ShouldEqual, zebedee.Bulletin)
+		So(string(data), ShouldContainSubstring, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	})
+}
@@ -0,0 +1,166 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/xmldoc"
+)
+
+// Content types of the rendered feeds.
+const (
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+	AtomContentType = "application/atom+xml; charset=utf-8"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description,omitempty"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	Category    string  `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Author   atomAuthor  `xml:"author"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Summary   string       `xml:"summary,omitempty"`
+	Published string       `xml:"published,omitempty"`
+	Updated   string       `xml:"updated"`
+	Link      atomLink     `xml:"link"`
+	Category  atomCategory `xml:"category"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// title returns the title of the feed, prefixed with the site title.
+func (feed *Feed) title() string {
+	if len(feed.Title) == 0 || feed.URI == "/" {
+		return feed.site.Title
+	}
+	return feed.site.Title + ": " + feed.Title
+}
+
+// RSS renders the feed as RSS 2.0. The link of each item, which is also its guid, is the canonical URL of the page.
+// selfURI is the uri the feed is served from.
+func (feed *Feed) RSS(selfURI string) ([]byte, error) {
+	channel := rssChannel{
+		Title:       feed.title(),
+		Link:        feed.site.CanonicalURI(feed.URI),
+		Description: feed.Summary,
+		Self:        atomLink{Href: feed.site.CanonicalURI(selfURI), Rel: "self", Type: "application/rss+xml"},
+		Items:       []rssItem{},
+	}
+	if len(channel.Description) == 0 {
+		channel.Description = feed.site.Description
+	}
+	if !feed.Updated.IsZero() {
+		channel.LastBuildDate = feed.Updated.Format(time.RFC1123Z)
+	}
+
+	for _, item := range feed.Items {
+		link := feed.site.CanonicalURI(item.URI)
+		rssItem := rssItem{
+			Title:       item.Title,
+			Link:        link,
+			Description: item.Summary,
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+			Category:    item.PageType,
+		}
+		if !item.Released.IsZero() {
+			rssItem.PubDate = item.Released.Format(time.RFC1123Z)
+		}
+		channel.Items = append(channel.Items, rssItem)
+	}
+
+	return xmldoc.Marshal(rss{Version: "2.0", Atom: atomNamespace, Channel: channel})
+}
+
+// Atom renders the feed as Atom. The id of each entry is the canonical URL of the page. selfURI is the uri the feed is
+// served from.
+func (feed *Feed) Atom(selfURI string) ([]byte, error) {
+	updated := feed.Updated
+	if updated.IsZero() {
+		updated = time.Now().UTC()
+	}
+
+	atom := atomFeed{
+		Xmlns:    atomNamespace,
+		ID:       feed.site.CanonicalURI(feed.URI),
+		Title:    feed.title(),
+		Subtitle: feed.Summary,
+		Updated:  updated.Format(time.RFC3339),
+		Author:   atomAuthor{Name: feed.site.Title},
+		Links: []atomLink{
+			{Href: feed.site.CanonicalURI(feed.URI)},
+			{Href: feed.site.CanonicalURI(selfURI), Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+
+	for _, item := range feed.Items {
+		link := feed.site.CanonicalURI(item.URI)
+		entry := atomEntry{
+			ID:       link,
+			Title:    item.Title,
+			Summary:  item.Summary,
+			Updated:  updated.Format(time.RFC3339),
+			Link:     atomLink{Href: link},
+			Category: atomCategory{Term: item.PageType},
+		}
+		if !item.Released.IsZero() {
+			entry.Published = item.Released.Format(time.RFC3339)
+			entry.Updated = entry.Published
+		}
+		atom.Entries = append(atom.Entries, entry)
+	}
+
+	return xmldoc.Marshal(atom)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/ONSdigital/dp-content-resolver/content/fields"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// maxBatchBodyBytes bounds the size of batch request bodies.
+const maxBatchBodyBytes = 1 << 20
+
+// batchRequest is the body of a batch resolve request.
+type batchRequest struct {
+	Requests []batchItem `json:"requests"`
+}
+
+// batchItem is a page to resolve in a batch, with its options.
+type batchItem struct {
+	URI     string       `json:"uri"`
+	Options batchOptions `json:"options"`
+}
+
+type batchOptions struct {
+	// Fields lists the fields of the page to resolve. Every field is resolved if none are listed.
+	Fields []string `json:"fields"`
+	// Timeline includes a timeline of the Zebedee calls made resolving the page, if timelines are allowed.
+	Timeline bool `json:"timeline"`
+}
+
+// batchResponse holds the result of resolving each page of a batch, in the order they were requested.
+type batchResponse struct {
+	Results []batchResult `json:"results"`
+}
+
+type batchResult struct {
+	URI      string          `json:"uri"`
+	Status   int             `json:"status"`
+	Page     json.RawMessage `json:"page,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Timeline *timeline       `json:"timeline,omitempty"`
+}
+
+// BatchHandle will resolve each of the pages in the request body concurrently, returning the result of each.
+// Identical Zebedee requests, such as for the taxonomy, are shared between the pages of the batch.
+func (handlers *Handlers) BatchHandle(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var batch batchRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxBatchBodyBytes)).Decode(&batch); err != nil {
+		writeErrorResponse(fmt.Errorf("invalid batch request body: %s", err), w)
+		return
+	}
+	uris, err := validateBatch(batch, handlers.options.BatchMaxURIs)
+	if err != nil {
+		writeErrorResponse(err, w)
+		return
+	}
+
+	log.DebugR(req, "Batch resolve handler", log.Data{"uris": len(batch.Requests)})
+
+	ctx := zebedee.WithSharedResponses(req.Context())
+	reqContextIDGen := requests.NewContentIDGenerator(req)
+
+	results := make([]batchResult, len(batch.Requests))
+	pool := make(chan struct{}, handlers.options.BatchConcurrency)
+	wg := new(sync.WaitGroup)
+
+	for i, item := range batch.Requests {
+		itemReq := req.Clone(ctx)
+		itemReq.Method = "GET"
+		itemReq.URL = uris[i]
+		itemReq.RequestURI = item.URI
+		if len(item.Options.Fields) > 0 {
+			query := itemReq.URL.Query()
+			query.Set(fields.Param, strings.Join(item.Options.Fields, ","))
+			itemReq.URL.RawQuery = query.Encode()
+		}
+		itemReq.Body = http.NoBody
+		itemReq.Header.Set(requests.RequestIDHeaderParam, reqContextIDGen.Generate())
+
+		wg.Add(1)
+		pool <- struct{}{}
+		go func(index int, item batchItem, itemReq *http.Request) {
+			defer func() {
+				<-pool
+				wg.Done()
+			}()
+			results[index] = handlers.resolveBatchItem(item, itemReq)
+		}(i, item, itemReq)
+	}
+	wg.Wait()
+
+	w.WriteHeader(200)
+	json.NewEncoder(w).Encode(batchResponse{Results: results})
+}
+
+// resolveBatchItem resolves a single page of a batch.
+func (handlers *Handlers) resolveBatchItem(item batchItem, req *http.Request) batchResult {
+	result := batchResult{URI: item.URI}
+
+	var recorded *zebedee.Timeline
+	if item.Options.Timeline && handlers.options.DebugTimeline {
+		ctx, timeline := zebedee.WithTimeline(req.Context())
+		req, recorded = req.WithContext(ctx), timeline
+	}
+
+	resolved, err := handlers.Resolve(req)
+	switch {
+	case err != nil && err.RootError == zebedee.ErrUnauthorised:
+		result.Status = http.StatusUnauthorized
+		result.Error = err.Error()
+	case err != nil:
+		log.ErrorR(req, err, nil)
+		result.Status = errorStatus(err)
+		result.Error = err.Error()
+	default:
+		result.Status = http.StatusOK
+		result.Page = resolved.Data
+	}
+
+	if recorded != nil {
+		summary := newTimeline(recorded)
+		result.Timeline = &summary
+	}
+	return result
+}
+
+// validateBatch returns the parsed uri of each page of the batch, or an error if the batch is empty, has more than the
+// maximum number of uris or contains an invalid uri.
+func validateBatch(batch batchRequest, maxURIs int) ([]*url.URL, error) {
+	if len(batch.Requests) == 0 {
+		return nil, errors.New("batch must contain at least one uri")
+	}
+	if len(batch.Requests) > maxURIs {
+		return nil, fmt.Errorf("batch must not contain more than %d uris, found %d", maxURIs, len(batch.Requests))
+	}
+
+	uris := make([]*url.URL, len(batch.Requests))
+	for i, item := range batch.Requests {
+		uri, err := url.ParseRequestURI(item.URI)
+		if err != nil || !strings.HasPrefix(item.URI, "/") {
+			return nil, fmt.Errorf("batch uris must be paths beginning with /, found %q", item.URI)
+		}
+		uris[i] = uri
+	}
+	return uris, nil
+}
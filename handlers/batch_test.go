@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ONSdigital/dp-content-resolver/content"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	"github.com/ONSdigital/go-ns/common"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBatchHandle(t *testing.T) {
+	var mutex sync.Mutex
+	requestIDs := make(map[string]string)
+
+	resolve := func(req *http.Request) (*content.Resolved, *common.ONSError) {
+		mutex.Lock()
+		requestIDs[req.URL.Path] = req.Header.Get(requests.RequestIDHeaderParam)
+		mutex.Unlock()
+
+		switch req.URL.Path {
+		case "/private":
+			return nil, common.NewONSError(zebedee.ErrUnauthorised, "")
+		case "/missing":
+			return nil, common.NewONSError(errors.New("not found"), "")
+		}
+		return &content.Resolved{Data: []byte(`{"uri":"` + req.URL.Path + `"}`)}, nil
+	}
+	handlers := &Handlers{Resolve: resolve, options: Options{BatchConcurrency: 8, BatchMaxURIs: 100}}
+
+	batch := func(body string) (*httptest.ResponseRecorder, batchResponse) {
+		req := httptest.NewRequest("POST", "/resolve/batch", strings.NewReader(body))
+		req.Header.Set(requests.RequestIDHeaderParam, "abc123")
+		w := httptest.NewRecorder()
+		handlers.BatchHandle(w, req)
+
+		var response batchResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response
+	}
+
+	Convey("Should return the result of each page in the order requested.", t, func() {
+		w, response := batch(`{"requests": [{"uri": "/"}, {"uri": "/missing"}, {"uri": "/private"}, {"uri": "/economy"}]}`)
+
+		So(w.Code, ShouldEqual, 200)
+		So(len(response.Results), ShouldEqual, 4)
+		So(response.Results[0].Status, ShouldEqual, 200)
+		So(string(response.Results[0].Page), ShouldEqual, `{"uri":"/"}`)
+		So(response.Results[1].Status, ShouldEqual, 400)
+		So(response.Results[1].Error, ShouldEqual, "not found")
+		So(response.Results[2].Status, ShouldEqual, 401)
+		So(string(response.Results[3].Page), ShouldEqual, `{"uri":"/economy"}`)
+
+		So(requestIDs["/"], ShouldEqual, "abc123.1")
+		So(requestIDs["/economy"], ShouldEqual, "abc123.4")
+	})
+
+	Convey("Should resolve each page with its query and requested fields.", t, func() {
+		var queries []string
+		handlers := &Handlers{options: handlers.options, Resolve: func(req *http.Request) (*content.Resolved, *common.ONSError) {
+			mutex.Lock()
+			queries = append(queries, req.URL.Path+"?"+req.URL.RawQuery)
+			mutex.Unlock()
+			return &content.Resolved{Data: []byte(`{}`)}, nil
+		}}
+
+		req := httptest.NewRequest("POST", "/resolve/batch", strings.NewReader(`{"requests": [{"uri": "/economy?lang=cy", "options": {"fields": ["breadcrumb"]}}]}`))
+		w := httptest.NewRecorder()
+		handlers.BatchHandle(w, req)
+
+		So(w.Code, ShouldEqual, 200)
+		So(queries, ShouldResemble, []string{"/economy?fields=breadcrumb&lang=cy"})
+	})
+
+	Convey("Should reject invalid batches.", t, func() {
+		for _, body := range []string{
+			`not json`,
+			`{"requests": []}`,
+			`{"requests": [{"uri": "economy"}]}`,
+			`{"requests": [{"uri": "http://example.com/economy"}]}`,
+			`{"requests": [{"uri": "/economy%zz"}]}`,
+			`{"requests": [` + strings.Repeat(`{"uri": "/"},`, handlers.options.BatchMaxURIs) + `{"uri": "/"}]}`,
+		} {
+			w, _ := batch(body)
+			So(w.Code, ShouldEqual, 400)
+		}
+	})
+}
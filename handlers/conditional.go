@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/content"
+)
+
+// defaultCacheControl is the key of the Cache-Control header used for page types without their own.
+const defaultCacheControl = "*"
+
+// degradedCacheControl is the Cache-Control header of pages with components that failed to resolve, so that caches
+// revalidate them rather than serve them until they expire.
+const degradedCacheControl = "no-cache"
+
+// writeCacheHeaders sets the ETag, Last-Modified and Cache-Control headers of the resolved page and returns true if the
+// client already holds it, in which case the caller responds 304.
+func (handlers *Handlers) writeCacheHeaders(w http.ResponseWriter, req *http.Request, resolved *content.Resolved) bool {
+	etag := contentETag(resolved.Data)
+	w.Header().Set("ETag", etag)
+
+	lastModified := resolved.LastModified.Truncate(time.Second)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if cacheControl := handlers.cacheControlFor(resolved); len(cacheControl) > 0 {
+		w.Header().Set("Cache-Control", cacheControl)
+	}
+
+	return notModified(req, etag, lastModified)
+}
+
+// contentETag returns the strong entity tag of the resolved page data, a hash of its content.
+func contentETag(data []byte) string {
+	hash := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(hash[:16]) + `"`
+}
+
+// cacheControlFor returns the Cache-Control header of the resolved page.
+func (handlers *Handlers) cacheControlFor(resolved *content.Resolved) string {
+	if resolved.Degraded() {
+		return degradedCacheControl
+	}
+	if cacheControl, ok := handlers.options.CacheControl[resolved.PageType]; ok {
+		return cacheControl
+	}
+	return handlers.options.CacheControl[defaultCacheControl]
+}
+
+// notModified returns true if the request is a conditional GET for the page with the given entity tag and last
+// modified time. If-None-Match takes precedence over If-Modified-Since.
+func notModified(req *http.Request, etag string, lastModified time.Time) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	if ifNoneMatch := req.Header.Get("If-None-Match"); len(ifNoneMatch) > 0 {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := req.Header.Get("If-Modified-Since"); len(ifModifiedSince) > 0 && !lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !lastModified.After(since)
+	}
+	return false
+}
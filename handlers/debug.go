@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+)
+
+// DebugHeader is the request header used to request debug output, as an alternative to the debug query parameter.
+const DebugHeader = "X-Debug"
+
+const debugParam = "debug"
+const debugTimeline = "timeline"
+
+// timelineResponse is returned in place of the resolved page when a timeline is requested.
+type timelineResponse struct {
+	Page         json.RawMessage `json:"page,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	Timeline     timeline        `json:"timeline"`
+	Dependencies []string        `json:"dependencies"`
+}
+
+type timeline struct {
+	DurationMs float64        `json:"durationMs"`
+	CallCount  int            `json:"callCount"`
+	CacheHits  int            `json:"cacheHits"`
+	Calls      []zebedee.Call `json:"calls"`
+}
+
+// timelineRequested returns true if the request asks for a timeline and timelines are allowed.
+func (handlers *Handlers) timelineRequested(req *http.Request) bool {
+	if !handlers.options.DebugTimeline {
+		return false
+	}
+	return req.URL.Query().Get(debugParam) == debugTimeline || req.Header.Get(DebugHeader) == debugTimeline
+}
+
+// writeTimelineResponse writes the resolved page data or error alongside the timeline of Zebedee calls and the uris
+// of the Zebedee content the page was built from.
+func writeTimelineResponse(w http.ResponseWriter, status int, data []byte, err error, recorded *zebedee.Timeline, dependencies *zebedee.Dependencies) {
+	response := timelineResponse{Page: data, Timeline: newTimeline(recorded), Dependencies: dependencies.URIs()}
+	if err != nil {
+		response.Error = err.Error()
+	}
+
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// newTimeline summarises the calls recorded in the timeline.
+func newTimeline(recorded *zebedee.Timeline) timeline {
+	summary := timeline{
+		DurationMs: float64(recorded.Elapsed()) / 1e6,
+		Calls:      recorded.Calls(),
+	}
+
+	summary.CallCount = len(summary.Calls)
+	for _, call := range summary.Calls {
+		if call.CacheHit {
+			summary.CacheHits++
+		}
+	}
+	return summary
+}
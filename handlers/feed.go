@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ONSdigital/dp-content-resolver/feed"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// RSSHandle will respond with the RSS feed of the publications beneath the page of the taxonomy defined by the path.
+func (handlers *Handlers) RSSHandle(w http.ResponseWriter, req *http.Request) {
+	handlers.writeFeed(w, req, feed.RSSContentType, (*feed.Feed).RSS)
+}
+
+// AtomHandle will respond with the Atom feed of the publications beneath the page of the taxonomy defined by the path.
+func (handlers *Handlers) AtomHandle(w http.ResponseWriter, req *http.Request) {
+	handlers.writeFeed(w, req, feed.AtomContentType, (*feed.Feed).Atom)
+}
+
+func (handlers *Handlers) writeFeed(w http.ResponseWriter, req *http.Request, contentType string, render func(*feed.Feed, string) ([]byte, error)) {
+	uri := "/" + req.URL.Query().Get(":uri")
+
+	log.DebugR(req, "Feed handler", log.Data{"uri": uri, "contentType": contentType})
+
+	found, err := handlers.FindFeed(req.Context(), uri, requests.NewContentIDGenerator(req))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if err.RootError == zebedee.ErrUnauthorised {
+			w.WriteHeader(401)
+			return
+		}
+
+		status := http.StatusBadRequest
+		if err.RootError == feed.ErrNotTaxonomyPage {
+			status = http.StatusNotFound
+		}
+		writeErrorResponseWithStatus(status, err, w)
+		log.ErrorR(req, err, nil)
+		return
+	}
+
+	data, renderErr := render(found, req.URL.Path)
+	if renderErr != nil {
+		log.ErrorR(req, renderErr, log.Data{"uri": uri})
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(200)
+	w.Write(data)
+}
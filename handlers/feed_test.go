@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-content-resolver/feed"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	"github.com/ONSdigital/go-ns/common"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFeedHandle(t *testing.T) {
+	handlers := &Handlers{FindFeed: func(ctx context.Context, uri string, reqContextIDGen *requests.ContextIDGenerator) (*feed.Feed, *common.ONSError) {
+		switch uri {
+		case "/private":
+			return nil, common.NewONSError(zebedee.ErrUnauthorised, "")
+		case "/economy/inflationandpriceindices/timeseries/d7g7":
+			return nil, common.NewONSError(feed.ErrNotTaxonomyPage, "")
+		}
+		return &feed.Feed{URI: uri, Title: "Economy"}, nil
+	}}
+
+	handle := func(uri string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/feed/rss"+uri+"?:uri="+uri[1:], nil)
+		w := httptest.NewRecorder()
+		handlers.RSSHandle(w, req)
+		return w
+	}
+
+	Convey("Should respond with the feed of the taxonomy page.", t, func() {
+		w := handle("/economy")
+		So(w.Code, ShouldEqual, 200)
+		So(w.Header().Get("Content-Type"), ShouldEqual, feed.RSSContentType)
+	})
+
+	Convey("Should return 404 for pages that are not part of the taxonomy.", t, func() {
+		So(handle("/economy/inflationandpriceindices/timeseries/d7g7").Code, ShouldEqual, 404)
+	})
+
+	Convey("Should return 401 if Zebedee does not authorise the request.", t, func() {
+		So(handle("/private").Code, ShouldEqual, 401)
+	})
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// FieldsHandle will list the fields that may be requested for each page type using the fields query parameter.
+func (handlers *Handlers) FieldsHandle(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	json.NewEncoder(w).Encode(handlers.options.Fields)
+}
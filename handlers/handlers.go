@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/ONSdigital/dp-content-resolver/content"
+	"github.com/ONSdigital/dp-content-resolver/content/homePage"
+	"github.com/ONSdigital/dp-content-resolver/feed"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/go-ns/common"
+)
+
+// Options controls how the handlers respond.
+type Options struct {
+	// DebugTimeline allows requests to include a timeline of the Zebedee calls made resolving them.
+	DebugTimeline bool
+	// BatchConcurrency is the maximum number of pages of a batch resolved at the same time.
+	BatchConcurrency int
+	// BatchMaxURIs is the maximum number of pages that may be resolved in a single batch.
+	BatchMaxURIs int
+	// CacheControl is the Cache-Control header of resolved pages keyed by page type, with the default keyed by *. No
+	// header is sent for page types without one.
+	CacheControl map[string]string
+	// Fields lists the fields that may be requested for each page type using the fields query parameter.
+	Fields map[string][]string
+}
+
+// Handlers serve resolved pages, sparklines and feeds. The functions they call are exported fields allowing
+// alternative implementations to be injected.
+type Handlers struct {
+	// Resolve is the function called to resolve page data.
+	Resolve content.ResolveFunc
+	// RenderSparkline is the function called to render a sparkline.
+	RenderSparkline func(context.Context, string, *requests.ContextIDGenerator) ([]byte, *common.ONSError)
+	// FindFeed is the function called to find the publications of a feed.
+	FindFeed func(context.Context, string, *requests.ContextIDGenerator) (*feed.Feed, *common.ONSError)
+
+	options Options
+}
+
+// New creates the handlers of the pages resolved by the resolver, the sparklines rendered by the homepage resolver and
+// the feeds found by the feed finder.
+func New(resolver *content.Resolver, homePageResolver *homePage.Resolver, feeds *feed.Finder, options Options) *Handlers {
+	return &Handlers{
+		Resolve:         resolver.Resolve,
+		RenderSparkline: homePageResolver.RenderSparkline,
+		FindFeed:        feeds.Find,
+		options:         options,
+	}
+}
@@ -3,43 +3,109 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/ONSdigital/dp-content-resolver/content"
 	"github.com/ONSdigital/dp-content-resolver/model"
 	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	"github.com/ONSdigital/go-ns/common"
 	"github.com/ONSdigital/go-ns/log"
 )
 
-// Resolve is the function called to resolve page data.
-// Its defined as an exported package member allowing
-// alternative implementations to be injected
-var Resolve = content.Resolve
+// DegradedHeader is the response header listing the components of a page that failed to resolve.
+const DegradedHeader = "X-Content-Degraded"
+
+// Response headers listing the uris of the Zebedee content each page was built from, so that CDN caches of the page
+// can be purged when any of it is published. Surrogate-Key is space separated and Cache-Tag comma separated.
+const (
+	SurrogateKeyHeader = "Surrogate-Key"
+	CacheTagHeader     = "Cache-Tag"
+)
 
 // Handle will resolve the page defined by the path.
-func Handle(w http.ResponseWriter, req *http.Request) {
+func (handlers *Handlers) Handle(w http.ResponseWriter, req *http.Request) {
 
 	log.DebugR(req, "Resolver handler", nil)
 
 	w.Header().Set("Content-Type", "application/json")
 
-	data, err := Resolve(req)
+	ctx, dependencies := zebedee.WithDependencies(req.Context())
+	req = req.WithContext(ctx)
+
+	var recorded *zebedee.Timeline
+	if handlers.timelineRequested(req) {
+		ctx, recorded = zebedee.WithTimeline(req.Context())
+		req = req.WithContext(ctx)
+	}
+
+	resolved, err := handlers.Resolve(req)
 	if err != nil {
 		if err.RootError == zebedee.ErrUnauthorised {
 			w.WriteHeader(401)
 			return
 		}
 
-		writeErrorResponse(err, w)
 		log.ErrorR(req, err, nil)
+		status := errorStatus(err)
+		if recorded != nil {
+			writeTimelineResponse(w, status, nil, err, recorded, dependencies)
+			return
+		}
+		writeErrorResponseWithStatus(status, err, w)
+		return
+	}
+
+	if resolved.Degraded() {
+		w.Header().Set(DegradedHeader, degradedComponents(resolved.Warnings))
+	}
+
+	if uris := dependencies.URIs(); len(uris) > 0 {
+		w.Header().Set(SurrogateKeyHeader, strings.Join(uris, " "))
+		w.Header().Set(CacheTagHeader, strings.Join(uris, ","))
+	}
+
+	if recorded != nil {
+		writeTimelineResponse(w, http.StatusOK, resolved.Data, nil, recorded, dependencies)
+		return
+	}
+
+	if handlers.writeCacheHeaders(w, req, resolved) {
+		w.Header().Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
 	w.WriteHeader(200)
-	w.Write(data)
+	w.Write(resolved.Data)
+}
+
+// degradedComponents returns the distinct components of the warnings as a comma separated list.
+func degradedComponents(warnings []model.Warning) string {
+	var components []string
+	seen := make(map[string]bool)
+	for _, warning := range warnings {
+		if !seen[warning.Component] {
+			seen[warning.Component] = true
+			components = append(components, warning.Component)
+		}
+	}
+	return strings.Join(components, ", ")
+}
+
+// errorStatus returns the response status for a page that failed to resolve.
+func errorStatus(err *common.ONSError) int {
+	if err.RootError == content.ErrMandatoryComponent {
+		return http.StatusBadGateway
+	}
+	return http.StatusBadRequest
 }
 
 func writeErrorResponse(err error, w http.ResponseWriter) {
-	w.WriteHeader(http.StatusBadRequest)
+	writeErrorResponseWithStatus(http.StatusBadRequest, err, w)
+}
+
+func writeErrorResponseWithStatus(status int, err error, w http.ResponseWriter) {
+	w.WriteHeader(status)
 	jsonEncoder := json.NewEncoder(w)
 	jsonEncoder.Encode(model.ErrorResponse{
 		Error: err.Error(),
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/content"
+	"github.com/ONSdigital/dp-content-resolver/content/homePage"
+	"github.com/ONSdigital/dp-content-resolver/feed"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	"github.com/ONSdigital/dp-content-resolver/zebedee/zebedeetest"
+	"github.com/ONSdigital/go-ns/common"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const headlineURI = "/economy/inflationandpriceindices/timeseries/d7g7"
+
+// homePageService serves every page of the Zebedee service it wraps as a homepage, so that pages with parents resolve.
+type homePageService struct {
+	zebedee.Service
+}
+
+func (service homePageService) GetData(ctx context.Context, uri string, requestContextID string) ([]byte, string, *common.ONSError) {
+	data, _, err := service.Service.GetData(ctx, uri, requestContextID)
+	return data, zebedee.HomePage, err
+}
+
+func TestHandle(t *testing.T) {
+	server := zebedeetest.NewServer("../zebedee/testdata/content")
+	defer server.Close()
+
+	client := zebedee.CreateClient(time.Second, server.URL)
+	homePageResolver := homePage.NewResolver(client, homePage.Options{TaxonomyDepth: 2})
+	handlers := New(content.NewResolver(client, homePageResolver, nil), homePageResolver, feed.NewFinder(client, feed.Options{}), Options{})
+
+	resolve := func(uri string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req := httptest.NewRequest("GET", uri, nil)
+		req.Header.Set(requests.RequestIDHeaderParam, "abc123")
+		w := httptest.NewRecorder()
+		handlers.Handle(w, req)
+
+		var page map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &page)
+		return w, page
+	}
+
+	Convey("Should resolve the homepage from Zebedee.", t, func() {
+		defer server.Reset()
+		w, page := resolve("/")
+
+		So(w.Code, ShouldEqual, 200)
+		So(w.Header().Get(DegradedHeader), ShouldBeEmpty)
+		So(page["degraded"], ShouldEqual, false)
+		So(len(page["taxonomy"].([]interface{})), ShouldEqual, 2)
+
+		headlines := page["data"].(map[string]interface{})["headlineFigures"].([]interface{})
+		So(len(headlines), ShouldEqual, 1)
+		So(headlines[0].(map[string]interface{})["uri"], ShouldEqual, headlineURI)
+
+		for _, request := range server.Requests() {
+			So(request.RequestContextID, ShouldStartWith, "abc123.")
+		}
+	})
+
+	Convey("Should list the Zebedee content the page was built from in surrogate key headers.", t, func() {
+		defer server.Reset()
+		w, _ := resolve("/")
+
+		So(w.Header().Get(SurrogateKeyHeader), ShouldEqual, "/ "+headlineURI)
+		So(w.Header().Get(CacheTagHeader), ShouldEqual, "/,"+headlineURI)
+	})
+
+	Convey("Should list the parents of the page in surrogate key headers.", t, func() {
+		defer server.Reset()
+		service := homePageService{Service: client}
+		homePageResolver := homePage.NewResolver(service, homePage.Options{TaxonomyDepth: 2})
+		parents := New(content.NewResolver(service, homePageResolver, nil), homePageResolver, feed.NewFinder(service, feed.Options{}), Options{})
+
+		req := httptest.NewRequest("GET", "/economy?fields=breadcrumb", nil)
+		w := httptest.NewRecorder()
+		parents.Handle(w, req)
+
+		So(w.Code, ShouldEqual, 200)
+		So(w.Header().Get(SurrogateKeyHeader), ShouldEqual, "/ /economy")
+	})
+
+	Convey("Should report components that fail to resolve.", t, func() {
+		defer server.Reset()
+		server.Script(zebedeetest.DataEndpoint, headlineURI, zebedeetest.Behaviour{Status: 500})
+		server.Script(zebedeetest.TaxonomyEndpoint, "", zebedeetest.Behaviour{Drop: true})
+
+		w, page := resolve("/")
+
+		So(w.Code, ShouldEqual, 200)
+		So(w.Header().Get(DegradedHeader), ShouldEqual, "taxonomy, headline")
+		So(page["degraded"], ShouldEqual, true)
+		So(len(page["warnings"].([]interface{})), ShouldEqual, 2)
+	})
+
+	Convey("Should respond 304 to conditional requests for an unchanged page.", t, func() {
+		defer server.Reset()
+		defer func() { handlers.options.CacheControl = nil }()
+		handlers.options.CacheControl = map[string]string{zebedee.HomePage: "public, max-age=60", "*": "no-cache"}
+
+		w, _ := resolve("/")
+		etag := w.Header().Get("ETag")
+		So(etag, ShouldStartWith, `"`)
+		So(w.Header().Get("Last-Modified"), ShouldEqual, "Tue, 15 Nov 2016 00:00:00 GMT")
+		So(w.Header().Get("Cache-Control"), ShouldEqual, "public, max-age=60")
+
+		conditional := func(header string, value string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(header, value)
+			w := httptest.NewRecorder()
+			handlers.Handle(w, req)
+			return w
+		}
+
+		w = conditional("If-None-Match", `"stale", W/`+etag)
+		So(w.Code, ShouldEqual, 304)
+		So(w.Body.Len(), ShouldEqual, 0)
+		So(w.Header().Get("ETag"), ShouldEqual, etag)
+		So(w.Header().Get("Cache-Control"), ShouldEqual, "public, max-age=60")
+
+		So(conditional("If-None-Match", `"stale"`).Code, ShouldEqual, 200)
+		So(conditional("If-Modified-Since", "Wed, 16 Nov 2016 00:00:00 GMT").Code, ShouldEqual, 304)
+		So(conditional("If-Modified-Since", "Mon, 14 Nov 2016 00:00:00 GMT").Code, ShouldEqual, 200)
+
+		w, _ = resolve("/?fields=breadcrumb")
+		So(w.Header().Get("ETag"), ShouldNotEqual, etag)
+	})
+
+	Convey("Should tell caches to revalidate degraded pages.", t, func() {
+		defer server.Reset()
+		defer func() { handlers.options.CacheControl = nil }()
+		handlers.options.CacheControl = map[string]string{zebedee.HomePage: "public, max-age=60"}
+		server.Script(zebedeetest.TaxonomyEndpoint, "", zebedeetest.Behaviour{Status: 500})
+
+		w, _ := resolve("/")
+		So(w.Header().Get("Cache-Control"), ShouldEqual, "no-cache")
+	})
+
+	Convey("Should return 502 if a mandatory component fails to resolve.", t, func() {
+		defer server.Reset()
+		server.Script(zebedeetest.TaxonomyEndpoint, "", zebedeetest.Behaviour{Status: 500})
+		mandatory := New(content.NewResolver(client, homePageResolver, map[string][]string{zebedee.HomePage: {homePage.ComponentTaxonomy}}),
+			homePageResolver, feed.NewFinder(client, feed.Options{}), Options{DebugTimeline: true})
+
+		req := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		mandatory.Handle(w, req)
+		So(w.Code, ShouldEqual, 502)
+		So(w.Body.String(), ShouldContainSubstring, content.ErrMandatoryComponent.Error())
+
+		req = httptest.NewRequest("GET", "/?debug=timeline", nil)
+		w = httptest.NewRecorder()
+		mandatory.Handle(w, req)
+		So(w.Code, ShouldEqual, 502)
+	})
+
+	Convey("Should return 401 if Zebedee does not authorise the request.", t, func() {
+		defer server.Reset()
+		server.Script(zebedeetest.DataEndpoint, "/", zebedeetest.Behaviour{Status: 401})
+
+		w, _ := resolve("/")
+		So(w.Code, ShouldEqual, 401)
+	})
+}
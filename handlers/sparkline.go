@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// SparklineHandle will render the sparkline of the timeseries defined by the path as SVG.
+func (handlers *Handlers) SparklineHandle(w http.ResponseWriter, req *http.Request) {
+	uri := "/" + req.URL.Query().Get(":uri")
+
+	log.DebugR(req, "Sparkline handler", log.Data{"uri": uri})
+
+	svg, err := handlers.RenderSparkline(req.Context(), uri, requests.NewContentIDGenerator(req))
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		if err.RootError == zebedee.ErrUnauthorised {
+			w.WriteHeader(401)
+			return
+		}
+
+		writeErrorResponse(err, w)
+		log.ErrorR(req, err, nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.WriteHeader(200)
+	w.Write(svg)
+}
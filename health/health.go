@@ -0,0 +1,172 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// Circuit states of a dependency. The circuit opens, failing readiness, once the dependency has failed the
+// configured number of consecutive checks and closes again on the next successful check.
+const (
+	circuitClosed = "closed"
+	circuitOpen   = "open"
+)
+
+// LivenessHandler responds 200 while the service is running.
+func LivenessHandler(w http.ResponseWriter, req *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// cacheReporter is implemented by zebedee services that cache responses.
+type cacheReporter interface {
+	CacheStats() zebedee.CacheStats
+}
+
+// Readiness is the readiness of the service and the state of its dependencies.
+type Readiness struct {
+	Ready    bool                `json:"ready"`
+	Draining bool                `json:"draining"`
+	Zebedee  DependencyState     `json:"zebedee"`
+	Cache    *zebedee.CacheStats `json:"cache,omitempty"`
+}
+
+// DependencyState is the result of the most recent checks of a dependency.
+type DependencyState struct {
+	Circuit             string     `json:"circuit"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastChecked         *time.Time `json:"lastChecked,omitempty"`
+	LastSuccess         *time.Time `json:"lastSuccess,omitempty"`
+	LastFailure         *time.Time `json:"lastFailure,omitempty"`
+	LastError           string     `json:"lastError,omitempty"`
+}
+
+// Checker periodically probes Zebedee and reports whether the service is ready to resolve pages.
+type Checker struct {
+	service   zebedee.Service
+	uri       string
+	interval  time.Duration
+	threshold int
+
+	mutex   sync.RWMutex
+	state   DependencyState
+	checked bool
+	count   int64
+
+	// draining is set once the service has started shutting down.
+	draining int32
+}
+
+// NewChecker creates a Checker that requests the given uri from Zebedee every interval, failing readiness after
+// threshold consecutive failures.
+func NewChecker(service zebedee.Service, uri string, interval time.Duration, threshold int) *Checker {
+	return &Checker{
+		service:   service,
+		uri:       uri,
+		interval:  interval,
+		threshold: threshold,
+		state:     DependencyState{Circuit: circuitClosed},
+	}
+}
+
+// SetDraining marks the service as shutting down, so readiness fails and it is taken out of service while in-flight
+// requests complete.
+func (c *Checker) SetDraining() {
+	atomic.StoreInt32(&c.draining, 1)
+}
+
+// IsDraining returns true once the service has started shutting down.
+func (c *Checker) IsDraining() bool {
+	return atomic.LoadInt32(&c.draining) == 1
+}
+
+// Start checks Zebedee immediately and then every interval until the context is done.
+func (c *Checker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			c.Check(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Check probes Zebedee once and records the result. Responses are never served from the cache.
+func (c *Checker) Check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(zebedee.WithoutCache(ctx), c.interval)
+	defer cancel()
+
+	requestContextID := "readiness-" + strconv.FormatInt(atomic.AddInt64(&c.count, 1), 10)
+	_, _, err := c.service.GetData(ctx, c.uri, requestContextID)
+	now := time.Now()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.checked = true
+	c.state.LastChecked = &now
+
+	if err != nil {
+		c.state.ConsecutiveFailures++
+		c.state.LastFailure = &now
+		c.state.LastError = err.Error()
+		if c.state.ConsecutiveFailures >= c.threshold && c.state.Circuit != circuitOpen {
+			c.state.Circuit = circuitOpen
+			log.ErrorC(requestContextID, err, log.Data{"description": "Zebedee readiness circuit opened", "uri": c.uri})
+		}
+		return
+	}
+
+	if c.state.Circuit == circuitOpen {
+		log.Debug("Zebedee readiness circuit closed", log.Data{"uri": c.uri})
+	}
+	c.state.ConsecutiveFailures = 0
+	c.state.LastSuccess = &now
+	c.state.LastError = ""
+	c.state.Circuit = circuitClosed
+}
+
+// Readiness returns the current readiness of the service. The service is not ready until Zebedee has been checked.
+func (c *Checker) Readiness() Readiness {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	readiness := Readiness{
+		Draining: c.IsDraining(),
+		Zebedee:  c.state,
+	}
+	readiness.Ready = c.checked && c.state.Circuit == circuitClosed && !readiness.Draining
+
+	if reporter, ok := c.service.(cacheReporter); ok {
+		stats := reporter.CacheStats()
+		readiness.Cache = &stats
+	}
+	return readiness
+}
+
+// ReadinessHandler responds with the readiness as JSON, with status 200 if the service is ready and 503 if not.
+func (c *Checker) ReadinessHandler(w http.ResponseWriter, req *http.Request) {
+	readiness := c.Readiness()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if readiness.Ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(readiness)
+}
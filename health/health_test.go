@@ -0,0 +1,109 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+	"github.com/ONSdigital/go-ns/common"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// zebedeeServiceMock fails GetData while failing is set.
+type zebedeeServiceMock struct {
+	failing bool
+}
+
+func (mock *zebedeeServiceMock) GetData(ctx context.Context, uri string, requestContextID string) ([]byte, string, *common.ONSError) {
+	if mock.failing {
+		return nil, "", common.NewONSError(errors.New("connection refused"), "")
+	}
+	return []byte("{}"), zebedee.HomePage, nil
+}
+
+func (mock *zebedeeServiceMock) GetTaxonomy(ctx context.Context, uri string, depth int, requestContextID string) ([]zebedeeModel.ContentNode, *common.ONSError) {
+	return nil, nil
+}
+
+func (mock *zebedeeServiceMock) GetParents(ctx context.Context, uri string, requestContextID string) ([]zebedeeModel.ContentNode, *common.ONSError) {
+	return nil, nil
+}
+
+func (mock *zebedeeServiceMock) GetTimeSeries(ctx context.Context, uri string, requestContextID string) (*zebedeeModel.TimeseriesPage, *common.ONSError) {
+	return nil, nil
+}
+
+func TestChecker(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Should not be ready before zebedee has been checked.", t, func() {
+		checker := NewChecker(&zebedeeServiceMock{}, "/", time.Second, 2)
+
+		So(checker.Readiness().Ready, ShouldBeFalse)
+	})
+
+	Convey("Should open the circuit after consecutive failures and close it on success.", t, func() {
+		service := &zebedeeServiceMock{}
+		checker := NewChecker(service, "/", time.Second, 2)
+
+		checker.Check(ctx)
+		So(checker.Readiness().Ready, ShouldBeTrue)
+		So(checker.Readiness().Zebedee.LastSuccess, ShouldNotBeNil)
+
+		service.failing = true
+		checker.Check(ctx)
+		So(checker.Readiness().Ready, ShouldBeTrue)
+		So(checker.Readiness().Zebedee.ConsecutiveFailures, ShouldEqual, 1)
+
+		checker.Check(ctx)
+		So(checker.Readiness().Ready, ShouldBeFalse)
+		So(checker.Readiness().Zebedee.Circuit, ShouldEqual, circuitOpen)
+		So(checker.Readiness().Zebedee.LastError, ShouldEqual, "connection refused")
+
+		service.failing = false
+		checker.Check(ctx)
+		So(checker.Readiness().Ready, ShouldBeTrue)
+		So(checker.Readiness().Zebedee.Circuit, ShouldEqual, circuitClosed)
+	})
+
+	Convey("Should report cache statistics for caching services.", t, func() {
+		client := zebedee.CreateClient(time.Second, "http://localhost:8082").EnableCache(time.Minute)
+		checker := NewChecker(client, "/", time.Second, 1)
+
+		So(checker.Readiness().Cache, ShouldNotBeNil)
+		So(checker.Readiness().Cache.Enabled, ShouldBeTrue)
+	})
+}
+
+func TestReadinessHandler(t *testing.T) {
+
+	Convey("Should respond 200 with the readiness JSON when ready.", t, func() {
+		checker := NewChecker(&zebedeeServiceMock{}, "/", time.Second, 1)
+		checker.Check(context.Background())
+
+		recorder := httptest.NewRecorder()
+		checker.ReadinessHandler(recorder, httptest.NewRequest("GET", "/readiness", nil))
+
+		var readiness Readiness
+		So(recorder.Code, ShouldEqual, 200)
+		So(json.Unmarshal(recorder.Body.Bytes(), &readiness), ShouldBeNil)
+		So(readiness.Ready, ShouldBeTrue)
+		So(readiness.Cache, ShouldBeNil)
+	})
+
+	Convey("Should respond 503 when draining.", t, func() {
+		checker := NewChecker(&zebedeeServiceMock{}, "/", time.Second, 1)
+		checker.Check(context.Background())
+		checker.SetDraining()
+
+		recorder := httptest.NewRecorder()
+		checker.ReadinessHandler(recorder, httptest.NewRequest("GET", "/readiness", nil))
+
+		So(recorder.Code, ShouldEqual, 503)
+	})
+}
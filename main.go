@@ -1,51 +1,243 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/config"
 	"github.com/ONSdigital/dp-content-resolver/content"
 	"github.com/ONSdigital/dp-content-resolver/content/homePage"
+	"github.com/ONSdigital/dp-content-resolver/content/metadata"
+	"github.com/ONSdigital/dp-content-resolver/feed"
 	"github.com/ONSdigital/dp-content-resolver/handlers"
+	"github.com/ONSdigital/dp-content-resolver/health"
+	"github.com/ONSdigital/dp-content-resolver/metrics"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/sitemap"
+	"github.com/ONSdigital/dp-content-resolver/tracing"
 	"github.com/ONSdigital/dp-content-resolver/zebedee"
-	"github.com/ONSdigital/go-ns/handlers/healthcheck"
-	"github.com/ONSdigital/go-ns/handlers/requestID"
 	"github.com/ONSdigital/go-ns/log"
 	"github.com/gorilla/pat"
 	"github.com/justinas/alice"
-	"net/http"
-	"os"
-	"time"
 )
 
 func main() {
-	bindAddr := os.Getenv("BIND_ADDR")
-	if len(bindAddr) == 0 {
-		bindAddr = ":20020"
+	log.Namespace = "dp-content-resolver"
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "resolve":
+			os.Exit(runResolve(os.Args[2:], os.Stdout, os.Stderr))
+		case "export":
+			os.Exit(runExport(os.Args[2:], os.Stdout, os.Stderr))
+		case "diff":
+			os.Exit(runDiff(os.Args[2:], os.Stdout, os.Stderr))
+		}
 	}
 
-	zebedeeURL := os.Getenv("ZEBEDEE_URL")
-	if len(zebedeeURL) == 0 {
-		zebedeeURL = "http://localhost:8082"
+	cfg, err := config.Load(os.Args[1:])
+	if err == flag.ErrHelp {
+		os.Exit(0)
 	}
+	if err != nil {
+		log.Error(err, nil)
+		os.Exit(1)
+	}
+	os.Exit(runServer(cfg))
+}
 
-	zebedeeSerivce := zebedee.CreateClient(time.Second*2, zebedeeURL)
-	content.ZebedeeService = zebedeeSerivce
-	homePage.ZebedeeService = zebedeeSerivce
+// runServer serves resolved pages until it receives SIGTERM or SIGINT, then shuts down. It returns the exit code, which
+// is non zero if the server could not be started or in-flight requests had to be cancelled to shut down, once deferred
+// cleanup such as flushing traces has run.
+func runServer(cfg *config.Config) int {
+	env, err := newEnvironment(cfg)
+	if err != nil {
+		log.Error(err, nil)
+		return 1
+	}
+	pageHandlers, err := newHandlers(cfg, env)
+	if err != nil {
+		log.Error(err, nil)
+		return 1
+	}
 
-	log.Namespace = "dp-content-resolver"
+	if len(cfg.OTLPEndpoint) > 0 {
+		exporter := tracing.NewOTLPExporter(cfg.OTLPEndpoint, log.Namespace, cfg.OTLPExportInterval)
+		exporter.Start()
+		tracing.SetExporter(exporter)
+		defer func() {
+			flushCtx, cancelFlush := context.WithTimeout(context.Background(), time.Second*2)
+			defer cancelFlush()
+			exporter.Shutdown(flushCtx)
+		}()
+	}
+
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	checker := health.NewChecker(env.zebedeeService, cfg.ReadinessURI, cfg.ReadinessInterval, cfg.ReadinessThreshold)
+	checker.Start(backgroundCtx)
+
+	sitemapGenerator := sitemap.NewGenerator(env.zebedeeService, sitemap.Options{
+		SiteDomain:  cfg.SiteDomain,
+		WelshDomain: cfg.SitemapWelshDomain,
+		MaxURLs:     cfg.SitemapMaxURLs,
+		Interval:    cfg.SitemapInterval,
+	})
+	sitemapGenerator.Start(backgroundCtx)
 
 	router := pat.New()
-	alice := alice.New(log.Handler, requestID.Handler(16)).Then(router)
+	alice := alice.New(log.Handler, requests.Handler(cfg.RequestIDLength), tracing.Handler).Then(router)
 
-	router.Get("/healthcheck", healthcheck.Handler)
+	router.Get("/healthcheck", health.LivenessHandler)
+	router.Get("/readiness", checker.ReadinessHandler)
+	router.Get("/metrics", metrics.Handler)
 
-	router.Get("/{uri:.*}", handlers.Handle)
+	router.Get("/sitemap.xml", sitemapGenerator.IndexHandler)
+	router.Get("/sitemap-{number:[0-9]+}.xml", sitemapGenerator.SitemapHandler)
+	router.Post("/resolve/batch", pageHandlers.BatchHandle)
+	router.Get("/resolve/fields", pageHandlers.FieldsHandle)
+	router.Get("/feed/rss/{uri:.*}", pageHandlers.RSSHandle)
+	router.Get("/feed/atom/{uri:.*}", pageHandlers.AtomHandle)
+	router.Get("/sparkline/{uri:.*}", pageHandlers.SparklineHandle)
+	router.Get("/{uri:.*}", pageHandlers.Handle)
 
-	log.Debug("Starting server", log.Data{
-		"bind_addr":   bindAddr,
-		"zebedee_url": zebedeeURL,
-	})
+	// Every request context, and so every outbound Zebedee request, is derived from this context so that they can be
+	// cancelled if in-flight requests do not complete within the shutdown timeout.
+	requestsCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	server := &http.Server{
+		Addr:        cfg.BindAddr,
+		Handler:     alice,
+		BaseContext: func(net.Listener) context.Context { return requestsCtx },
+	}
+
+	log.Debug("Starting server", cfg.LogData())
+
+	serverErrors := make(chan error, 1)
+	go func() {
+		serverErrors <- server.ListenAndServe()
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
 
-	if err := http.ListenAndServe(bindAddr, alice); err != nil {
+	select {
+	case err := <-serverErrors:
 		log.Error(err, nil)
-		os.Exit(1)
+		return 1
+	case sig := <-signals:
+		log.Debug("Shutting down", log.Data{"signal": sig.String(), "drainDelay": cfg.DrainDelay.String(), "timeout": cfg.ShutdownTimeout.String()})
+	}
+
+	// keep serving until Consul has seen readiness fail and stopped routing requests here. A second signal skips the wait.
+	checker.SetDraining()
+	select {
+	case <-time.After(cfg.DrainDelay):
+	case sig := <-signals:
+		log.Debug("Skipping drain delay", log.Data{"signal": sig.String()})
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelShutdown()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Error(err, log.Data{"description": "In-flight requests did not complete before the shutdown timeout, cancelling them."})
+		cancelRequests()
+
+		// Give the cancelled requests a moment to respond before closing their connections.
+		closeCtx, cancelClose := context.WithTimeout(context.Background(), time.Second)
+		defer cancelClose()
+		if err := server.Shutdown(closeCtx); err != nil {
+			server.Close()
+		}
+		return 1
 	}
+
+	log.Debug("Shutdown complete", nil)
+	return 0
+}
+
+// environment holds a Zebedee service and the resolvers requesting content from it.
+type environment struct {
+	zebedeeService zebedee.Service
+	homePage       *homePage.Resolver
+	content        *content.Resolver
+	feeds          *feed.Finder
+}
+
+// newEnvironment creates the Zebedee service and the resolvers using it from the configuration. An error is returned
+// if the mandatory components cannot be parsed.
+func newEnvironment(cfg *config.Config) (*environment, error) {
+	mandatoryComponents, err := cfg.MandatoryComponentsByPageType()
+	if err != nil {
+		return nil, err
+	}
+
+	site := metadata.Defaults{
+		SiteDomain:  cfg.SiteDomain,
+		Title:       cfg.MetadataTitle,
+		Description: cfg.MetadataDescription,
+		Keywords:    cfg.MetadataKeywords,
+	}
+
+	zebedeeClient := zebedee.CreateClient(cfg.ZebedeeTimeout, cfg.ZebedeeURL)
+	if len(cfg.ZebedeeReplayDir) > 0 {
+		zebedeeClient = zebedee.NewReplay(cfg.ZebedeeReplayDir)
+	}
+	if len(cfg.ZebedeeRecordDir) > 0 {
+		zebedeeClient.EnableRecording(cfg.ZebedeeRecordDir)
+	}
+
+	var zebedeeService zebedee.Service = zebedeeClient.EnableCache(cfg.ZebedeeCacheTTL)
+	if len(cfg.ZebedeeContentDir) > 0 {
+		zebedeeService = zebedee.NewFileSystem(cfg.ZebedeeContentDir)
+	}
+
+	homePageResolver := homePage.NewResolver(zebedeeService, homePage.Options{
+		TaxonomyDepth: cfg.TaxonomyDepth,
+		Sparkline: homePage.SparklineOptions{
+			Frequency:   cfg.SparklineFrequency,
+			LastPeriods: cfg.SparklinePeriods,
+			LastYears:   cfg.SparklineYears,
+			MaxPoints:   cfg.SparklineMaxPoints,
+			EmbedSVG:    cfg.SparklineSVG,
+		},
+		Metadata: site,
+	})
+
+	return &environment{
+		zebedeeService: zebedeeService,
+		homePage:       homePageResolver,
+		content:        content.NewResolver(zebedeeService, homePageResolver, mandatoryComponents),
+		feeds: feed.NewFinder(zebedeeService, feed.Options{
+			MaxItems:      cfg.FeedMaxItems,
+			TaxonomyDepth: cfg.FeedTaxonomyDepth,
+			MaxLinks:      cfg.FeedMaxLinks,
+			CacheTTL:      cfg.FeedCacheTTL,
+			Site:          site,
+		}),
+	}, nil
+}
+
+// newHandlers creates the handlers of the resolvers of the environment from the configuration. An error is returned if
+// the cache control headers cannot be parsed.
+func newHandlers(cfg *config.Config, env *environment) (*handlers.Handlers, error) {
+	cacheControl, err := cfg.CacheControlByPageType()
+	if err != nil {
+		return nil, err
+	}
+
+	return handlers.New(env.content, env.homePage, env.feeds, handlers.Options{
+		DebugTimeline:    cfg.DebugTimeline,
+		BatchConcurrency: cfg.BatchConcurrency,
+		BatchMaxURIs:     cfg.BatchMaxURIs,
+		CacheControl:     cacheControl,
+		Fields:           content.SupportedFields(),
+	}), nil
 }
@@ -0,0 +1,335 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// Namespace prefixes the name of every metric created by this package.
+const Namespace = "dp_content_resolver"
+
+// DefaultBuckets are the upper bounds, in seconds, of the histogram buckets for request durations.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metric is implemented by every metric type so the registry can write them in the Prometheus text format.
+type metric interface {
+	name() string
+	write(buf *bytes.Buffer)
+}
+
+var (
+	registryMutex sync.Mutex
+	registry      []metric
+)
+
+var _ = NewGaugeFunc("goroutines", "Number of goroutines that currently exist.", func() float64 {
+	return float64(runtime.NumGoroutine())
+})
+
+func register(m metric) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry = append(registry, m)
+}
+
+// Handler writes every registered metric in the Prometheus text exposition format.
+func Handler(w http.ResponseWriter, req *http.Request) {
+	registryMutex.Lock()
+	metrics := make([]metric, len(registry))
+	copy(metrics, registry)
+	registryMutex.Unlock()
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].name() < metrics[j].name() })
+
+	var buf bytes.Buffer
+	for _, m := range metrics {
+		m.write(&buf)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// descriptor holds the fields common to every metric type.
+type descriptor struct {
+	fullName   string
+	help       string
+	labelNames []string
+}
+
+func newDescriptor(name string, help string, labelNames []string) descriptor {
+	return descriptor{fullName: Namespace + "_" + name, help: help, labelNames: labelNames}
+}
+
+func (d descriptor) name() string {
+	return d.fullName
+}
+
+func (d descriptor) writeHeader(buf *bytes.Buffer, metricType string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", d.fullName, d.help, d.fullName, metricType)
+}
+
+// key joins label values into a single map key. It returns false, and the sample should be dropped, if the number of
+// label values does not match the label names of the metric.
+func (d descriptor) key(labelValues []string) (string, bool) {
+	if len(labelValues) != len(d.labelNames) {
+		log.Error(errors.New("wrong number of metric label values"), log.Data{
+			"metric":   d.fullName,
+			"expected": len(d.labelNames),
+			"found":    len(labelValues),
+		})
+		return "", false
+	}
+	return strings.Join(labelValues, "\xff"), true
+}
+
+// labels formats the label pairs for the key, with any extra pair appended.
+func (d descriptor) labels(key string, extra ...string) string {
+	var pairs []string
+	if len(d.labelNames) > 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			pairs = append(pairs, d.labelNames[i]+"="+quoteLabelValue(value))
+		}
+	}
+	if len(extra) == 2 {
+		pairs = append(pairs, extra[0]+"="+quoteLabelValue(extra[1]))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// labelValueEscaper escapes label values as the Prometheus text format requires. Unlike Go string literals, only
+// backslashes, double quotes and line feeds are escaped and every other character is written as UTF-8.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabelValue(value string) string {
+	return `"` + labelValueEscaper.Replace(value) + `"`
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// Counter is a cumulative metric partitioned by labels.
+type Counter struct {
+	descriptor
+	mutex  sync.Mutex
+	values map[string]float64
+}
+
+// NewCounter creates and registers a counter with the given label names.
+func NewCounter(name string, help string, labelNames ...string) *Counter {
+	c := &Counter{descriptor: newDescriptor(name, help, labelNames), values: make(map[string]float64)}
+	register(c)
+	return c
+}
+
+// Inc increments the counter for the label values by one.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increments the counter for the label values by the given amount.
+func (c *Counter) Add(value float64, labelValues ...string) {
+	key, ok := c.key(labelValues)
+	if !ok {
+		return
+	}
+	c.mutex.Lock()
+	c.values[key] += value
+	c.mutex.Unlock()
+}
+
+// Value returns the current value of the counter for the label values.
+func (c *Counter) Value(labelValues ...string) float64 {
+	key, ok := c.key(labelValues)
+	if !ok {
+		return 0
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.values[key]
+}
+
+func (c *Counter) write(buf *bytes.Buffer) {
+	c.writeHeader(buf, "counter")
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	keys := make(map[string]bool, len(c.values))
+	for key := range c.values {
+		keys[key] = true
+	}
+	for _, key := range sortedKeys(keys) {
+		fmt.Fprintf(buf, "%s%s %s\n", c.fullName, c.labels(key), formatValue(c.values[key]))
+	}
+}
+
+// Gauge is a metric that can go up and down.
+type Gauge struct {
+	descriptor
+	mutex sync.Mutex
+	value float64
+}
+
+// NewGauge creates and registers a gauge.
+func NewGauge(name string, help string) *Gauge {
+	g := &Gauge{descriptor: newDescriptor(name, help, nil)}
+	register(g)
+	return g
+}
+
+// Inc increments the gauge by one.
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+// Dec decrements the gauge by one.
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+// Add adds the given amount to the gauge.
+func (g *Gauge) Add(value float64) {
+	g.mutex.Lock()
+	g.value += value
+	g.mutex.Unlock()
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() float64 {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.value
+}
+
+func (g *Gauge) write(buf *bytes.Buffer) {
+	g.writeHeader(buf, "gauge")
+	fmt.Fprintf(buf, "%s %s\n", g.fullName, formatValue(g.Value()))
+}
+
+// GaugeFunc is a gauge whose value is read from a function each time the metrics are written.
+type GaugeFunc struct {
+	descriptor
+	value func() float64
+}
+
+// NewGaugeFunc creates and registers a gauge whose value is returned by the function.
+func NewGaugeFunc(name string, help string, value func() float64) *GaugeFunc {
+	g := &GaugeFunc{descriptor: newDescriptor(name, help, nil), value: value}
+	register(g)
+	return g
+}
+
+func (g *GaugeFunc) write(buf *bytes.Buffer) {
+	g.writeHeader(buf, "gauge")
+	fmt.Fprintf(buf, "%s %s\n", g.fullName, formatValue(g.value()))
+}
+
+// Histogram samples observations into buckets, partitioned by labels.
+type Histogram struct {
+	descriptor
+	buckets []float64
+	mutex   sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram creates and registers a histogram with the given bucket upper bounds and label names.
+func NewHistogram(name string, help string, buckets []float64, labelNames ...string) *Histogram {
+	h := &Histogram{
+		descriptor: newDescriptor(name, help, labelNames),
+		buckets:    buckets,
+		series:     make(map[string]*histogramSeries),
+	}
+	register(h)
+	return h
+}
+
+// Observe adds an observation for the label values.
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	key, ok := h.key(labelValues)
+	if !ok {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = series
+	}
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			series.counts[i]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+// Count returns the number of observations for the label values.
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	key, ok := h.key(labelValues)
+	if !ok {
+		return 0
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if series, ok := h.series[key]; ok {
+		return series.count
+	}
+	return 0
+}
+
+func (h *Histogram) write(buf *bytes.Buffer) {
+	h.writeHeader(buf, "histogram")
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	keys := make(map[string]bool, len(h.series))
+	for key := range h.series {
+		keys[key] = true
+	}
+	for _, key := range sortedKeys(keys) {
+		series := h.series[key]
+		for i, upperBound := range h.buckets {
+			fmt.Fprintf(buf, "%s_bucket%s %d\n", h.fullName, h.labels(key, "le", formatValue(upperBound)), series.counts[i])
+		}
+		fmt.Fprintf(buf, "%s_bucket%s %d\n", h.fullName, h.labels(key, "le", "+Inf"), series.count)
+		fmt.Fprintf(buf, "%s_sum%s %s\n", h.fullName, h.labels(key), formatValue(series.sum))
+		fmt.Fprintf(buf, "%s_count%s %d\n", h.fullName, h.labels(key), series.count)
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHandler(t *testing.T) {
+	counter := NewCounter("test_requests_total", "Test requests.", "status")
+	gauge := NewGauge("test_in_flight", "Test requests in flight.")
+	histogram := NewHistogram("test_duration_seconds", "Test durations.", []float64{0.1, 1}, "endpoint")
+
+	Convey("Should write each metric in the prometheus text format.", t, func() {
+		counter.Inc("200")
+		counter.Add(2, "500")
+		gauge.Inc()
+		histogram.Observe(0.05, "/data")
+		histogram.Observe(0.5, "/data")
+
+		recorder := httptest.NewRecorder()
+		Handler(recorder, httptest.NewRequest("GET", "/metrics", nil))
+		body := recorder.Body.String()
+
+		So(recorder.Code, ShouldEqual, 200)
+		So(body, ShouldContainSubstring, "# TYPE dp_content_resolver_test_requests_total counter\n")
+		So(body, ShouldContainSubstring, "dp_content_resolver_test_requests_total{status=\"200\"} 1\n")
+		So(body, ShouldContainSubstring, "dp_content_resolver_test_requests_total{status=\"500\"} 2\n")
+		So(body, ShouldContainSubstring, "dp_content_resolver_test_in_flight 1\n")
+		So(body, ShouldContainSubstring, "dp_content_resolver_test_duration_seconds_bucket{endpoint=\"/data\",le=\"0.1\"} 1\n")
+		So(body, ShouldContainSubstring, "dp_content_resolver_test_duration_seconds_bucket{endpoint=\"/data\",le=\"1\"} 2\n")
+		So(body, ShouldContainSubstring, "dp_content_resolver_test_duration_seconds_bucket{endpoint=\"/data\",le=\"+Inf\"} 2\n")
+		So(body, ShouldContainSubstring, "dp_content_resolver_test_duration_seconds_sum{endpoint=\"/data\"} 0.55\n")
+		So(body, ShouldContainSubstring, "dp_content_resolver_test_duration_seconds_count{endpoint=\"/data\"} 2\n")
+		So(body, ShouldContainSubstring, "# TYPE dp_content_resolver_goroutines gauge\n")
+	})
+
+	Convey("Should escape label values as the prometheus text format requires.", t, func() {
+		counter.Inc("é \"\\\n\t")
+
+		recorder := httptest.NewRecorder()
+		Handler(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+		So(recorder.Body.String(), ShouldContainSubstring, "dp_content_resolver_test_requests_total{status=\"é \\\"\\\\\\n\t\"} 1\n")
+	})
+
+	Convey("Should drop samples with the wrong number of label values.", t, func() {
+		So(func() { counter.Inc() }, ShouldNotPanic)
+		So(func() { histogram.Observe(1, "/data", "extra") }, ShouldNotPanic)
+		So(counter.Value("200"), ShouldEqual, 1)
+		So(histogram.Count("/data"), ShouldEqual, 2)
+	})
+}
@@ -0,0 +1,7 @@
+package model
+
+// Link is a resolved link to another page.
+type Link struct {
+	Title string `json:"title"`
+	URI   string `json:"uri"`
+}
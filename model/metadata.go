@@ -0,0 +1,11 @@
+package model
+
+// Metadata is the resolved metadata common to every page type.
+type Metadata struct {
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Keywords     []string `json:"keywords"`
+	CanonicalURI string   `json:"canonicalUri"`
+	ReleaseDate  string   `json:"releaseDate,omitempty"`
+	PageType     string   `json:"pageType"`
+}
@@ -0,0 +1,22 @@
+package model
+
+// Warning describes a component of a page that failed to resolve. The page is still returned without it.
+type Warning struct {
+	Component  string `json:"component"`
+	ResolveURI string `json:"resolveUri"`
+	Error      string `json:"error"`
+}
+
+// Status reports whether any components of a resolved page failed to resolve. It is included in every resolved page.
+type Status struct {
+	Degraded bool      `json:"degraded"`
+	Warnings []Warning `json:"warnings"`
+}
+
+// NewStatus returns the status of a page resolved with the given warnings.
+func NewStatus(warnings []Warning) Status {
+	if warnings == nil {
+		warnings = make([]Warning, 0)
+	}
+	return Status{Degraded: len(warnings) > 0, Warnings: warnings}
+}
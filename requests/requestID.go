@@ -0,0 +1,50 @@
+package requests
+
+import (
+	"crypto/rand"
+	"net/http"
+)
+
+// DefaultRequestIDLength is the length of request IDs generated when no length is configured.
+const DefaultRequestIDLength = 16
+
+var requestIDLetters = []byte("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+
+// NewRequestID generates a random request ID of the given length. IDs are read from a cryptographically secure source
+// so they do not repeat across process restarts.
+func NewRequestID(length int) string {
+	id := make([]byte, 0, length)
+	buf := make([]byte, length)
+
+	// reject bytes that would bias the distribution towards the start of the alphabet.
+	max := byte(256 - 256%len(requestIDLetters))
+	for len(id) < length {
+		if _, err := rand.Read(buf); err != nil {
+			panic(err)
+		}
+		for _, b := range buf {
+			if b < max && len(id) < length {
+				id = append(id, requestIDLetters[int(b)%len(requestIDLetters)])
+			}
+		}
+	}
+	return string(id)
+}
+
+// Handler is a wrapper which adds an X-Request-Id header of the given length to the request if one does not yet
+// exist, and echoes the request ID in the response X-Request-Id header.
+func Handler(length int) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			requestID := req.Header.Get(RequestIDHeaderParam)
+
+			if len(requestID) == 0 {
+				requestID = NewRequestID(length)
+				req.Header.Set(RequestIDHeaderParam, requestID)
+			}
+			w.Header().Set(RequestIDHeaderParam, requestID)
+
+			h.ServeHTTP(w, req)
+		})
+	}
+}
@@ -1,26 +1,52 @@
 package requests
 
 import (
-	"fmt"
-	"math/rand"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 )
 
 // RequestIDHeaderParam request header parameter name for unique request ID.
 const RequestIDHeaderParam = "X-Request-Id"
-const requestIDFmt = "%s-%v"
 
-// ContextIDGenerator generates a unique request header value based on the in bound request X-Request-Id header
+// requestIDSeparator separates each level of a hierarchical request context ID.
+const requestIDSeparator = "."
+
+// ContextIDGenerator generates unique, hierarchical request context IDs based on the in bound request X-Request-Id
+// header. Each generated ID is the parent ID followed by a sequence number, e.g. abc123.3, and a Child generator
+// extends this to another level, e.g. abc123.3.1. It is safe for concurrent use.
 type ContextIDGenerator struct {
-	originalRequest *http.Request
+	parentID string
+	sequence uint64
+}
+
+// NewContentIDGenerator creates a new ContextIDGenerator using the X-Request-Id header of the provided http.Request,
+// generating a new request ID if the header is not set.
+func NewContentIDGenerator(req *http.Request) *ContextIDGenerator {
+	parentID := req.Header.Get(RequestIDHeaderParam)
+	if len(parentID) == 0 {
+		parentID = NewRequestID(DefaultRequestIDLength)
+	}
+	return NewContextIDGenerator(parentID)
 }
 
-// NewContentIDGenerator creates a new ContextIDGenerator using the provided http.Request
-func NewContentIDGenerator(req *http.Request) ContextIDGenerator {
-	return ContextIDGenerator{originalRequest: req}
+// NewContextIDGenerator creates a new ContextIDGenerator generating IDs beneath the given parent ID.
+func NewContextIDGenerator(parentID string) *ContextIDGenerator {
+	return &ContextIDGenerator{parentID: parentID}
+}
+
+// ParentID returns the ID that generated IDs are beneath.
+func (r *ContextIDGenerator) ParentID() string {
+	return r.parentID
 }
 
 // Generate generates a unique RequestContextID to be used to communicate with zebedee.
-func (r ContextIDGenerator) Generate() string {
-	return fmt.Sprintf(requestIDFmt, r.originalRequest.Header.Get(RequestIDHeaderParam), rand.Int63n(1000))
+func (r *ContextIDGenerator) Generate() string {
+	return r.parentID + requestIDSeparator + strconv.FormatUint(atomic.AddUint64(&r.sequence, 1), 10)
+}
+
+// Child generates a unique ID and returns a new ContextIDGenerator generating IDs beneath it, allowing the
+// sub-requests of a resolve step to be grouped under the step.
+func (r *ContextIDGenerator) Child() *ContextIDGenerator {
+	return NewContextIDGenerator(r.Generate())
 }
@@ -0,0 +1,86 @@
+package requests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestContextIDGenerator(t *testing.T) {
+
+	Convey("Should generate sequential IDs beneath the inbound request ID.", t, func() {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeaderParam, "abc123")
+		generator := NewContentIDGenerator(req)
+
+		So(generator.Generate(), ShouldEqual, "abc123.1")
+		So(generator.Generate(), ShouldEqual, "abc123.2")
+
+		child := generator.Child()
+		So(child.ParentID(), ShouldEqual, "abc123.3")
+		So(child.Generate(), ShouldEqual, "abc123.3.1")
+		So(generator.Generate(), ShouldEqual, "abc123.4")
+	})
+
+	Convey("Should generate a parent ID when the inbound request has none.", t, func() {
+		first := NewContentIDGenerator(httptest.NewRequest("GET", "/", nil))
+		second := NewContentIDGenerator(httptest.NewRequest("GET", "/", nil))
+
+		So(len(first.ParentID()), ShouldEqual, DefaultRequestIDLength)
+		So(first.ParentID(), ShouldNotEqual, second.ParentID())
+	})
+
+	Convey("Should not generate the same ID concurrently.", t, func() {
+		generator := NewContextIDGenerator("abc123")
+		ids := make(chan string, 1000)
+
+		wg := new(sync.WaitGroup)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					ids <- generator.Generate()
+				}
+			}()
+		}
+		wg.Wait()
+		close(ids)
+
+		seen := make(map[string]bool)
+		for id := range ids {
+			seen[id] = true
+		}
+		So(len(seen), ShouldEqual, 1000)
+	})
+}
+
+func TestHandler(t *testing.T) {
+	var requestID string
+	handler := Handler(10)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requestID = req.Header.Get(RequestIDHeaderParam)
+	}))
+
+	Convey("Should generate a request ID and echo it in the response.", t, func() {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		So(len(requestID), ShouldEqual, 10)
+		So(strings.Trim(requestID, string(requestIDLetters)), ShouldBeEmpty)
+		So(w.Header().Get(RequestIDHeaderParam), ShouldEqual, requestID)
+	})
+
+	Convey("Should echo the inbound request ID in the response.", t, func() {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeaderParam, "abc123")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		So(requestID, ShouldEqual, "abc123")
+		So(w.Header().Get(RequestIDHeaderParam), ShouldEqual, "abc123")
+	})
+}
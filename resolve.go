@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ONSdigital/dp-content-resolver/config"
+	"github.com/ONSdigital/dp-content-resolver/handlers"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+const resolveUsage = "usage: dp-content-resolver resolve [flags] <uri>"
+
+// resolveOutput is printed by the resolve command.
+type resolveOutput struct {
+	URI          string          `json:"uri"`
+	Status       int             `json:"status"`
+	Degraded     string          `json:"degraded,omitempty"`
+	Warnings     json.RawMessage `json:"warnings,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	Timeline     json.RawMessage `json:"timeline,omitempty"`
+	Dependencies []string        `json:"dependencies,omitempty"`
+	Page         json.RawMessage `json:"page,omitempty"`
+}
+
+// runResolve resolves the uri given after the flags in args, as the server would, and writes the resolved page along
+// with the Zebedee calls made and any components that failed to resolve to stdout. Errors are logged to stderr. It
+// returns the exit code, which is non zero if the page could not be resolved.
+func runResolve(args []string, stdout io.Writer, stderr io.Writer) int {
+	logTo(stderr)
+
+	cfg, err := config.Load(args)
+	if err == flag.ErrHelp {
+		fmt.Fprintln(stderr, resolveUsage)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if len(cfg.Args) != 1 {
+		fmt.Fprintln(stderr, resolveUsage)
+		return 2
+	}
+
+	uri, err := url.ParseRequestURI(cfg.Args[0])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if !strings.HasPrefix(cfg.Args[0], "/") {
+		fmt.Fprintf(stderr, "uri must be a path beginning with /, found %q\n", cfg.Args[0])
+		return 2
+	}
+
+	cfg.DebugTimeline = true
+	env, err := newEnvironment(cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	pageHandlers, err := newHandlers(cfg, env)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	query := uri.Query()
+	query.Set("debug", "timeline")
+	uri.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", uri.String(), nil)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	req.Header.Set(requests.RequestIDHeaderParam, requests.NewRequestID(cfg.RequestIDLength))
+
+	w := newResponseBuffer()
+	pageHandlers.Handle(w, req)
+
+	output := resolveOutput{URI: uri.Path, Status: w.Code, Degraded: w.Header().Get(handlers.DegradedHeader)}
+
+	var response struct {
+		Page         json.RawMessage `json:"page"`
+		Error        string          `json:"error"`
+		Timeline     json.RawMessage `json:"timeline"`
+		Dependencies []string        `json:"dependencies"`
+	}
+	if w.Code == http.StatusUnauthorized {
+		output.Error = "unauthorised"
+	} else if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		output.Error = err.Error()
+	} else {
+		output.Page, output.Error, output.Timeline = response.Page, response.Error, response.Timeline
+		output.Dependencies = response.Dependencies
+	}
+
+	var page struct {
+		Warnings json.RawMessage `json:"warnings"`
+	}
+	if len(output.Page) > 0 && json.Unmarshal(output.Page, &page) == nil {
+		output.Warnings = page.Warnings
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(output)
+
+	if w.Code != http.StatusOK {
+		return 1
+	}
+	return 0
+}
+
+// responseBuffer holds the response written by a handler in memory.
+type responseBuffer struct {
+	Code   int
+	Body   bytes.Buffer
+	header http.Header
+}
+
+func newResponseBuffer() *responseBuffer {
+	return &responseBuffer{Code: http.StatusOK, header: make(http.Header)}
+}
+
+func (w *responseBuffer) Header() http.Header {
+	return w.header
+}
+
+func (w *responseBuffer) Write(data []byte) (int, error) {
+	return w.Body.Write(data)
+}
+
+func (w *responseBuffer) WriteHeader(status int) {
+	w.Code = status
+}
+
+// logTo writes log events other than debug events to w, so that they are kept apart from the output of a command.
+func logTo(w io.Writer) {
+	log.Event = func(name string, context string, data log.Data) {
+		if name != "debug" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"event": name, "context": context, "data": data})
+		}
+	}
+}
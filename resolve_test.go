@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ONSdigital/go-ns/log"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRunResolve(t *testing.T) {
+	defer func(event func(string, string, log.Data)) { log.Event = event }(log.Event)
+
+	resolve := func(args ...string) (int, resolveOutput, string) {
+		var stdout, stderr bytes.Buffer
+		code := runResolve(args, &stdout, &stderr)
+
+		var output resolveOutput
+		json.Unmarshal(stdout.Bytes(), &output)
+		return code, output, stderr.String()
+	}
+
+	Convey("Should print the resolved page along with the Zebedee calls made.", t, func() {
+		code, output, _ := resolve("-zebedee-content-dir", "zebedee/testdata/content", "/?fields=breadcrumb,headlineFigures")
+
+		So(code, ShouldEqual, 0)
+		So(output.URI, ShouldEqual, "/")
+		So(output.Status, ShouldEqual, 200)
+		So(string(output.Warnings), ShouldEqual, "[]")
+		So(string(output.Page), ShouldContainSubstring, `"headlineFigures"`)
+		So(string(output.Page), ShouldNotContainSubstring, `"taxonomy"`)
+		So(string(output.Timeline), ShouldContainSubstring, `"calls"`)
+		So(output.Dependencies, ShouldResemble, []string{"/", "/economy", "/economy/inflationandpriceindices/timeseries/d7g7"})
+	})
+
+	Convey("Should exit non zero if the page cannot be resolved.", t, func() {
+		code, output, stderr := resolve("-zebedee-content-dir", "zebedee/testdata/content", "/business")
+
+		So(code, ShouldEqual, 1)
+		So(output.Status, ShouldEqual, 400)
+		So(output.Error, ShouldEqual, "content not found")
+		So(stderr, ShouldContainSubstring, "content not found")
+	})
+
+	Convey("Should exit 2 if the uri is not a path.", t, func() {
+		for _, uri := range []string{"economy", "http://example.com/economy", "/economy%zz"} {
+			code, _, stderr := resolve("-zebedee-content-dir", "zebedee/testdata/content", uri)
+
+			So(code, ShouldEqual, 2)
+			So(stderr, ShouldNotBeEmpty)
+		}
+	})
+
+	Convey("Should print usage without a uri.", t, func() {
+		code, _, stderr := resolve()
+
+		So(code, ShouldEqual, 2)
+		So(stderr, ShouldContainSubstring, resolveUsage)
+	})
+}
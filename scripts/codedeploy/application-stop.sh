@@ -3,5 +3,5 @@
 CONTAINER_ID=$(docker ps | grep content-resolver | awk '{print $1}')
 
 if [[ -n $CONTAINER_ID ]]; then
-  docker stop $CONTAINER_ID
+  docker stop --time=30 $CONTAINER_ID
 fi
@@ -0,0 +1,319 @@
+// Package sitemap generates the sitemap of the site for search engines by walking the Zebedee taxonomy and the pages
+// published beneath it.
+package sitemap
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/metrics"
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/xmldoc"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// MaxURLs is the maximum number of URLs a sitemap may hold under the sitemap protocol.
+const MaxURLs = 50000
+
+// Languages of the alternate URLs of each page.
+const (
+	languageEnglish = "en"
+	languageWelsh   = "cy"
+)
+
+const (
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	xhtmlNamespace   = "http://www.w3.org/1999/xhtml"
+	lastModFormat    = "2006-01-02"
+)
+
+var generations = metrics.NewCounter("sitemap_generations_total", "Number of sitemap generations by result.", "result")
+
+// Options controls the content of the sitemap.
+type Options struct {
+	// SiteDomain is prefixed to each page uri to build its URL.
+	SiteDomain string
+	// WelshDomain is prefixed to each page uri to build the URL of its Welsh alternate. Empty omits alternates.
+	WelshDomain string
+	// MaxURLs is the maximum number of URLs in each sitemap before the sitemap is split and listed by a sitemap index.
+	MaxURLs int
+	// Interval is how often the sitemap is regenerated.
+	Interval time.Duration
+}
+
+// URL is a page listed in a sitemap.
+type URL struct {
+	Loc        string      `xml:"loc"`
+	LastMod    string      `xml:"lastmod,omitempty"`
+	Alternates []Alternate `xml:"xhtml:link"`
+}
+
+// Alternate is the URL of a page in another language.
+type Alternate struct {
+	Rel      string `xml:"rel,attr"`
+	HrefLang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	XHTML   string   `xml:"xmlns:xhtml,attr,omitempty"`
+	URLs    []URL    `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapRef `xml:"sitemap"`
+}
+
+type sitemapRef struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Generator periodically walks the site and holds the generated sitemaps in memory.
+type Generator struct {
+	service zebedee.Service
+	options Options
+
+	mutex    sync.RWMutex
+	index    []byte
+	sitemaps [][]byte
+}
+
+// NewGenerator creates a Generator that finds the pages of the site using the Zebedee service.
+func NewGenerator(service zebedee.Service, options Options) *Generator {
+	if options.MaxURLs <= 0 || options.MaxURLs > MaxURLs {
+		options.MaxURLs = MaxURLs
+	}
+	return &Generator{service: service, options: options}
+}
+
+// Start generates the sitemap immediately and then every interval until the context is done.
+func (g *Generator) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(g.options.Interval)
+		defer ticker.Stop()
+
+		for {
+			if err := g.Generate(ctx); err != nil && ctx.Err() == nil {
+				log.Error(err, log.Data{"description": "Failed to generate the sitemap, serving the previous sitemap."})
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Generate walks the site and replaces the sitemaps served. The previous sitemaps are kept if the site cannot be walked.
+func (g *Generator) Generate(ctx context.Context) error {
+	urls, err := g.walk(ctx)
+	if err != nil {
+		generations.Inc("error")
+		return err
+	}
+
+	var sitemaps [][]byte
+	var refs []sitemapRef
+	for start := 0; start < len(urls); start += g.options.MaxURLs {
+		end := start + g.options.MaxURLs
+		if end > len(urls) {
+			end = len(urls)
+		}
+
+		set := urlSet{Xmlns: sitemapNamespace, URLs: urls[start:end]}
+		if len(g.options.WelshDomain) > 0 {
+			set.XHTML = xhtmlNamespace
+		}
+		sitemap, err := xmldoc.Marshal(set)
+		if err != nil {
+			generations.Inc("error")
+			return err
+		}
+		sitemaps = append(sitemaps, sitemap)
+		refs = append(refs, sitemapRef{
+			Loc:     g.options.SiteDomain + "/sitemap-" + strconv.Itoa(len(sitemaps)) + ".xml",
+			LastMod: latest(urls[start:end]),
+		})
+	}
+
+	var index []byte
+	if len(sitemaps) > 1 {
+		if index, err = xmldoc.Marshal(sitemapIndex{Xmlns: sitemapNamespace, Sitemaps: refs}); err != nil {
+			generations.Inc("error")
+			return err
+		}
+	}
+
+	g.mutex.Lock()
+	g.index, g.sitemaps = index, sitemaps
+	g.mutex.Unlock()
+
+	generations.Inc("success")
+	log.Debug("Generated sitemap", log.Data{"urls": len(urls), "sitemaps": len(sitemaps)})
+	return nil
+}
+
+// walk returns the URL of the homepage, every page in the taxonomy and every page linked to from them, ordered by uri.
+func (g *Generator) walk(ctx context.Context) ([]URL, error) {
+	reqContextIDGen := requests.NewContextIDGenerator("sitemap-" + requests.NewRequestID(8))
+	lastMods := make(map[string]string)
+
+	data, _, err := g.service.GetData(ctx, "/", reqContextIDGen.Generate())
+	if err != nil {
+		return nil, err
+	}
+	lastMods["/"] = releaseDate(data)
+	published := zebedeeModel.PageLinks("/", data)
+
+	taxonomy := []string{"/"}
+	for len(taxonomy) > 0 {
+		uri := taxonomy[0]
+		taxonomy = taxonomy[1:]
+
+		nodes, err := g.service.GetTaxonomy(ctx, uri, 1, reqContextIDGen.Generate())
+		if err != nil {
+			if uri == "/" {
+				return nil, err
+			}
+			log.Error(err, log.Data{"description": "Failed to get the taxonomy beneath a sitemap page.", "uri": uri})
+			continue
+		}
+
+		for _, node := range nodes {
+			if _, seen := lastMods[node.URI]; seen {
+				continue
+			}
+			lastMods[node.URI] = formatLastMod(node.Description.ReleaseDate)
+			taxonomy = append(taxonomy, node.URI)
+
+			if data, _, err := g.service.GetData(ctx, node.URI, reqContextIDGen.Generate()); err == nil {
+				published = append(published, zebedeeModel.PageLinks(node.URI, data)...)
+			}
+		}
+	}
+
+	for _, uri := range published {
+		if _, seen := lastMods[uri]; seen {
+			continue
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// only pages that are published are listed, along with the date they were released.
+		data, _, err := g.service.GetData(ctx, uri, reqContextIDGen.Generate())
+		if err != nil {
+			continue
+		}
+		lastMods[uri] = releaseDate(data)
+	}
+
+	urls := make([]URL, 0, len(lastMods))
+	for uri, lastMod := range lastMods {
+		urls = append(urls, g.url(uri, lastMod))
+	}
+	sort.Slice(urls, func(i, j int) bool { return urls[i].Loc < urls[j].Loc })
+	return urls, nil
+}
+
+// url returns the sitemap URL of the page at the uri, with its Welsh alternate if configured.
+func (g *Generator) url(uri string, lastMod string) URL {
+	url := URL{Loc: g.options.SiteDomain + uri, LastMod: lastMod}
+	if len(g.options.WelshDomain) > 0 {
+		url.Alternates = []Alternate{
+			{Rel: "alternate", HrefLang: languageEnglish, Href: url.Loc},
+			{Rel: "alternate", HrefLang: languageWelsh, Href: g.options.WelshDomain + uri},
+		}
+	}
+	return url
+}
+
+// IndexHandler serves the sitemap, or the sitemap index if the site has been split into several sitemaps. It responds
+// 503 until the sitemap has been generated.
+func (g *Generator) IndexHandler(w http.ResponseWriter, req *http.Request) {
+	g.mutex.RLock()
+	index, sitemaps := g.index, g.sitemaps
+	g.mutex.RUnlock()
+
+	switch {
+	case len(sitemaps) == 0:
+		writeUnavailable(w)
+	case index != nil:
+		writeXML(w, index)
+	default:
+		writeXML(w, sitemaps[0])
+	}
+}
+
+// SitemapHandler serves the numbered sitemap listed by the sitemap index.
+func (g *Generator) SitemapHandler(w http.ResponseWriter, req *http.Request) {
+	g.mutex.RLock()
+	sitemaps := g.sitemaps
+	g.mutex.RUnlock()
+
+	if len(sitemaps) == 0 {
+		writeUnavailable(w)
+		return
+	}
+
+	number, err := strconv.Atoi(req.URL.Query().Get(":number"))
+	if err != nil || number < 1 || number > len(sitemaps) {
+		http.NotFound(w, req)
+		return
+	}
+	writeXML(w, sitemaps[number-1])
+}
+
+func writeXML(w http.ResponseWriter, data []byte) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func writeUnavailable(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", "60")
+	http.Error(w, "sitemap not yet generated", http.StatusServiceUnavailable)
+}
+
+// releaseDate returns the formatted release date of the Zebedee page content, or an empty string if it has none.
+func releaseDate(data []byte) string {
+	var page struct {
+		Description zebedeeModel.PageDescription `json:"description"`
+	}
+	json.Unmarshal(data, &page)
+	return formatLastMod(page.Description.ReleaseDate)
+}
+
+// formatLastMod formats a Zebedee release date as a sitemap date, or returns an empty string if it cannot be parsed.
+func formatLastMod(releaseDate string) string {
+	released, err := time.Parse(time.RFC3339, releaseDate)
+	if err != nil {
+		return ""
+	}
+	return released.UTC().Format(lastModFormat)
+}
+
+// latest returns the most recent last modified date of the URLs.
+func latest(urls []URL) string {
+	var lastMod string
+	for _, url := range urls {
+		if url.LastMod > lastMod {
+			lastMod = url.LastMod
+		}
+	}
+	return lastMod
+}
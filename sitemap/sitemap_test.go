@@ -0,0 +1,97 @@
+package sitemap
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testContentDir = "../zebedee/testdata/content"
+
+func TestGenerate(t *testing.T) {
+	service := zebedee.NewFileSystem(testContentDir)
+
+	Convey("Should respond 503 until the sitemap has been generated.", t, func() {
+		generator := NewGenerator(service, Options{SiteDomain: "https://www.ons.gov.uk"})
+
+		w := httptest.NewRecorder()
+		generator.IndexHandler(w, httptest.NewRequest("GET", "/sitemap.xml", nil))
+		So(w.Code, ShouldEqual, 503)
+	})
+
+	Convey("Should list the homepage, the taxonomy and the pages published beneath it.", t, func() {
+		generator := NewGenerator(service, Options{SiteDomain: "https://www.ons.gov.uk", WelshDomain: "https://cy.ons.gov.uk"})
+		So(generator.Generate(context.Background()), ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		generator.IndexHandler(w, httptest.NewRequest("GET", "/sitemap.xml", nil))
+		So(w.Code, ShouldEqual, 200)
+		So(w.Header().Get("Content-Type"), ShouldStartWith, "application/xml")
+		So(w.Body.String(), ShouldContainSubstring, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:xhtml="http://www.w3.org/1999/xhtml">`)
+
+		var set urlSet
+		So(xml.Unmarshal(w.Body.Bytes(), &set), ShouldBeNil)
+
+		var locs []string
+		for _, url := range set.URLs {
+			locs = append(locs, url.Loc)
+		}
+		So(locs, ShouldResemble, []string{
+			"https://www.ons.gov.uk/",
+			"https://www.ons.gov.uk/economy",
+			"https://www.ons.gov.uk/economy/grossdomesticproductgdp",
+			"https://www.ons.gov.uk/economy/inflationandpriceindices",
+			"https://www.ons.gov.uk/economy/inflationandpriceindices/articles/shoppingpricecomparisontool/2016-11-15",
+			"https://www.ons.gov.uk/economy/inflationandpriceindices/bulletins/consumerpriceinflation/oct2016",
+			"https://www.ons.gov.uk/economy/inflationandpriceindices/bulletins/consumerpriceinflation/sept2016",
+			"https://www.ons.gov.uk/economy/inflationandpriceindices/datasets/consumerpriceinflation",
+			"https://www.ons.gov.uk/economy/inflationandpriceindices/timeseries/d7g7",
+			"https://www.ons.gov.uk/employmentandlabourmarket",
+		})
+
+		timeseries := set.URLs[8]
+		So(timeseries.LastMod, ShouldEqual, "2016-11-15")
+		So(w.Body.String(), ShouldContainSubstring,
+			`<xhtml:link rel="alternate" hreflang="cy" href="https://cy.ons.gov.uk/economy/inflationandpriceindices/timeseries/d7g7"></xhtml:link>`)
+	})
+
+	Convey("Should split large sites into several sitemaps listed by a sitemap index.", t, func() {
+		generator := NewGenerator(service, Options{SiteDomain: "https://www.ons.gov.uk", MaxURLs: 5})
+		So(generator.Generate(context.Background()), ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		generator.IndexHandler(w, httptest.NewRequest("GET", "/sitemap.xml", nil))
+
+		var index sitemapIndex
+		So(xml.Unmarshal(w.Body.Bytes(), &index), ShouldBeNil)
+		So(index.Sitemaps, ShouldResemble, []sitemapRef{
+			{Loc: "https://www.ons.gov.uk/sitemap-1.xml", LastMod: "2016-11-15"},
+			{Loc: "https://www.ons.gov.uk/sitemap-2.xml", LastMod: "2016-11-15"},
+		})
+
+		w = httptest.NewRecorder()
+		generator.SitemapHandler(w, httptest.NewRequest("GET", "/sitemap-2.xml?:number=2", nil))
+		So(w.Code, ShouldEqual, 200)
+		So(w.Body.String(), ShouldContainSubstring, "<loc>https://www.ons.gov.uk/employmentandlabourmarket</loc>")
+
+		w = httptest.NewRecorder()
+		generator.SitemapHandler(w, httptest.NewRequest("GET", "/sitemap-3.xml?:number=3", nil))
+		So(w.Code, ShouldEqual, 404)
+	})
+
+	Convey("Should keep the previous sitemap if the site cannot be walked.", t, func() {
+		generator := NewGenerator(service, Options{SiteDomain: "https://www.ons.gov.uk"})
+		So(generator.Generate(context.Background()), ShouldBeNil)
+
+		generator.service = zebedee.NewFileSystem("missing")
+		So(generator.Generate(context.Background()), ShouldNotBeNil)
+
+		w := httptest.NewRecorder()
+		generator.IndexHandler(w, httptest.NewRequest("GET", "/sitemap.xml", nil))
+		So(w.Code, ShouldEqual, 200)
+	})
+}
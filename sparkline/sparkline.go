@@ -0,0 +1,103 @@
+package sparkline
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+
+	"github.com/ONSdigital/dp-frontend-models/model/homepage"
+)
+
+// Chart holds the data rendered as a sparkline.
+type Chart struct {
+	// ID is used to prefix the element ids of the SVG, so several sparklines can be embedded in one document.
+	ID      string
+	Title   string
+	Summary string
+	Points  []homepage.SparklineData
+}
+
+// Options controls the size and styling of the rendered SVG.
+type Options struct {
+	Width       int
+	Height      int
+	StrokeWidth float64
+	Colour      string
+}
+
+// DefaultOptions are the options used to render headline figure sparklines.
+var DefaultOptions = Options{
+	Width:       120,
+	Height:      30,
+	StrokeWidth: 1.5,
+	Colour:      "currentColor",
+}
+
+// Render draws the chart as an accessible SVG line. The title and summary are included as the SVG title and
+// description so screen readers announce the trend rather than the line.
+func Render(chart Chart, options Options) []byte {
+	titleID := chart.ID + "-title"
+	descID := chart.ID + "-desc"
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-labelledby="%s %s">`,
+		options.Width, options.Height, options.Width, options.Height, html.EscapeString(titleID), html.EscapeString(descID))
+	fmt.Fprintf(&buf, `<title id="%s">%s</title>`, html.EscapeString(titleID), html.EscapeString(chart.Title))
+	fmt.Fprintf(&buf, `<desc id="%s">%s</desc>`, html.EscapeString(descID), html.EscapeString(chart.Summary))
+
+	points := plot(chart.Points, options)
+	if len(points) > 0 {
+		buf.WriteString(`<polyline fill="none" stroke="` + html.EscapeString(options.Colour) + `" stroke-width="` +
+			formatFloat(options.StrokeWidth) + `" stroke-linejoin="round" stroke-linecap="round" points="`)
+		for i, point := range points {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			buf.WriteString(formatFloat(point[0]) + "," + formatFloat(point[1]))
+		}
+		buf.WriteString(`"/>`)
+
+		last := points[len(points)-1]
+		fmt.Fprintf(&buf, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`, formatFloat(last[0]), formatFloat(last[1]),
+			formatFloat(options.StrokeWidth*1.5), html.EscapeString(options.Colour))
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
+
+// plot scales the points to the SVG coordinates, leaving room for the stroke and end marker at the edges.
+func plot(data []homepage.SparklineData, options Options) [][2]float64 {
+	if len(data) == 0 {
+		return nil
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, point := range data {
+		min = math.Min(min, float64(point.Y))
+		max = math.Max(max, float64(point.Y))
+	}
+
+	margin := options.StrokeWidth * 1.5
+	width := float64(options.Width) - 2*margin
+	height := float64(options.Height) - 2*margin
+
+	points := make([][2]float64, len(data))
+	for i, point := range data {
+		x, y := 0.5, 0.5
+		if len(data) > 1 {
+			x = float64(i) / float64(len(data)-1)
+		}
+		if max > min {
+			y = (float64(point.Y) - min) / (max - min)
+		}
+		points[i] = [2]float64{margin + x*width, margin + (1-y)*height}
+	}
+	return points
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
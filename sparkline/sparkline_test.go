@@ -0,0 +1,49 @@
+package sparkline
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/ONSdigital/dp-frontend-models/model/homepage"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type svg struct {
+	Title    string `xml:"title"`
+	Desc     string `xml:"desc"`
+	Polyline struct {
+		Points string `xml:"points,attr"`
+	} `xml:"polyline"`
+}
+
+func TestRender(t *testing.T) {
+
+	Convey("Should render a valid SVG with an accessible title and description.", t, func() {
+		chart := Chart{
+			ID:      "cpi",
+			Title:   "CPI <% change>",
+			Summary: "Up 0.4 from 2016 AUG.",
+			Points: []homepage.SparklineData{
+				{Name: "2016 JUL", Y: 0.6},
+				{Name: "2016 AUG", Y: 0.6},
+				{Name: "2016 SEP", Y: 1},
+			},
+		}
+
+		var actual svg
+		err := xml.Unmarshal(Render(chart, Options{Width: 100, Height: 20, StrokeWidth: 2, Colour: "#000"}), &actual)
+
+		So(err, ShouldBeNil)
+		So(actual.Title, ShouldEqual, chart.Title)
+		So(actual.Desc, ShouldEqual, chart.Summary)
+		So(actual.Polyline.Points, ShouldEqual, "3,17 50,17 97,3")
+	})
+
+	Convey("Should render an empty chart without a line.", t, func() {
+		var actual svg
+		err := xml.Unmarshal(Render(Chart{Title: "Empty"}, DefaultOptions), &actual)
+
+		So(err, ShouldBeNil)
+		So(actual.Polyline.Points, ShouldBeEmpty)
+	})
+}
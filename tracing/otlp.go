@@ -0,0 +1,219 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/go-ns/log"
+)
+
+const otlpTracesPath = "/v1/traces"
+
+// Status codes of exported spans, as defined by OpenTelemetry.
+const (
+	statusUnset = 0
+	statusError = 2
+)
+
+// OTLPExporter batches spans and sends them to an OpenTelemetry collector using OTLP over HTTP with JSON encoding.
+// Spans are dropped rather than blocking requests if the collector cannot keep up.
+type OTLPExporter struct {
+	url         string
+	serviceName string
+	interval    time.Duration
+	batchSize   int
+	httpClient  *http.Client
+	spans       chan *Span
+	done        chan struct{}
+	flushed     chan struct{}
+}
+
+// NewOTLPExporter creates an exporter sending spans to the collector at the endpoint, e.g. http://localhost:4318,
+// every interval.
+func NewOTLPExporter(endpoint string, serviceName string, interval time.Duration) *OTLPExporter {
+	return &OTLPExporter{
+		url:         strings.TrimSuffix(endpoint, "/") + otlpTracesPath,
+		serviceName: serviceName,
+		interval:    interval,
+		batchSize:   512,
+		httpClient:  &http.Client{Timeout: time.Second * 5},
+		spans:       make(chan *Span, 4096),
+		done:        make(chan struct{}),
+		flushed:     make(chan struct{}),
+	}
+}
+
+// Export queues the span to be sent to the collector.
+func (e *OTLPExporter) Export(span *Span) {
+	select {
+	case e.spans <- span:
+	default:
+		log.Debug("Tracing export queue full, dropping span", log.Data{"span": span.Name})
+	}
+}
+
+// Start sends queued spans to the collector until Shutdown is called.
+func (e *OTLPExporter) Start() {
+	go func() {
+		defer close(e.flushed)
+
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		batch := make([]*Span, 0, e.batchSize)
+		for {
+			select {
+			case span := <-e.spans:
+				batch = append(batch, span)
+				if len(batch) >= e.batchSize {
+					e.send(batch)
+					batch = batch[:0]
+				}
+			case <-ticker.C:
+				e.send(batch)
+				batch = batch[:0]
+			case <-e.done:
+				for {
+					select {
+					case span := <-e.spans:
+						batch = append(batch, span)
+					default:
+						e.send(batch)
+						return
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Shutdown sends any queued spans to the collector, waiting until they are sent or the context is done.
+func (e *OTLPExporter) Shutdown(ctx context.Context) {
+	close(e.done)
+	select {
+	case <-e.flushed:
+	case <-ctx.Done():
+	}
+}
+
+func (e *OTLPExporter) send(spans []*Span) {
+	if len(spans) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		log.Error(err, log.Data{"description": "failed to encode spans"})
+		return
+	}
+
+	response, err := e.httpClient.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Error(err, log.Data{"description": "failed to export spans", "url": e.url, "spans": len(spans)})
+		return
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		log.Error(fmt.Errorf("unexpected status code %d", response.StatusCode), log.Data{"description": "failed to export spans", "url": e.url, "spans": len(spans)})
+	}
+}
+
+// The types below are the parts of the OTLP JSON trace request used by the exporter.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func (e *OTLPExporter) request(spans []*Span) otlpRequest {
+	otlpSpans := make([]otlpSpan, len(spans))
+	for i, span := range spans {
+		otlpSpans[i] = otlpSpan{
+			TraceID:           hex.EncodeToString(span.TraceID[:]),
+			SpanID:            hex.EncodeToString(span.SpanID[:]),
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        attributes(span.Attributes()),
+			Status:            otlpStatus{Code: statusUnset},
+		}
+		if span.ParentSpanID != (SpanID{}) {
+			otlpSpans[i].ParentSpanID = hex.EncodeToString(span.ParentSpanID[:])
+		}
+		if err := span.Error(); len(err) > 0 {
+			otlpSpans[i].Status = otlpStatus{Code: statusError, Message: err}
+		}
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: attributes(map[string]string{"service.name": e.serviceName})},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: e.serviceName}, Spans: otlpSpans}},
+	}}}
+}
+
+func attributes(values map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]otlpAttribute, len(keys))
+	for i, key := range keys {
+		attrs[i] = otlpAttribute{Key: key, Value: otlpValue{StringValue: values[key]}}
+	}
+	return attrs
+}
@@ -0,0 +1,220 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceparentHeader is the W3C trace context header propagated between services.
+const TraceparentHeader = "traceparent"
+
+// Span kinds, as defined by OpenTelemetry.
+const (
+	KindInternal = 1
+	KindServer   = 2
+	KindClient   = 3
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// Span records a single timed operation of a trace.
+type Span struct {
+	TraceID      TraceID
+	SpanID       SpanID
+	ParentSpanID SpanID
+	Name         string
+	Kind         int
+	Sampled      bool
+	Start        time.Time
+	End          time.Time
+
+	mutex      sync.Mutex
+	attributes map[string]string
+	err        string
+	ended      bool
+}
+
+type spanKey struct{}
+
+// exporter receives every sampled span when it ends. Spans are discarded if it is nil.
+var exporter Exporter
+
+// Exporter sends ended spans to a tracing backend.
+type Exporter interface {
+	Export(span *Span)
+}
+
+// SetExporter sets the exporter that receives every sampled span.
+func SetExporter(e Exporter) {
+	exporter = e
+}
+
+// FromContext returns the current span of the context, or nil if there is none.
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// StartSpan starts a new span as a child of the current span of the context, or as the root of a new trace if there
+// is none. The returned context holds the new span.
+func StartSpan(ctx context.Context, name string, kind int) (context.Context, *Span) {
+	span := &Span{Name: name, Kind: kind, Start: time.Now(), SpanID: newSpanID()}
+
+	if parent := FromContext(ctx); parent != nil {
+		span.TraceID = parent.TraceID
+		span.ParentSpanID = parent.SpanID
+		span.Sampled = parent.Sampled
+	} else {
+		span.TraceID = newTraceID()
+		span.Sampled = true
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SetAttribute records a key value pair on the span.
+func (span *Span) SetAttribute(key string, value interface{}) {
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+
+	if span.attributes == nil {
+		span.attributes = make(map[string]string)
+	}
+	span.attributes[key] = fmt.Sprint(value)
+}
+
+// SetError marks the span as failed with the given error.
+func (span *Span) SetError(err error) {
+	if err == nil {
+		return
+	}
+	span.mutex.Lock()
+	span.err = err.Error()
+	span.mutex.Unlock()
+}
+
+// Attributes returns a copy of the attributes recorded on the span.
+func (span *Span) Attributes() map[string]string {
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+
+	attributes := make(map[string]string, len(span.attributes))
+	for key, value := range span.attributes {
+		attributes[key] = value
+	}
+	return attributes
+}
+
+// Error returns the error recorded on the span, if any.
+func (span *Span) Error() string {
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+	return span.err
+}
+
+// Finish ends the span and exports it if it is sampled. Finishing a span more than once has no effect.
+func (span *Span) Finish() {
+	span.mutex.Lock()
+	if span.ended {
+		span.mutex.Unlock()
+		return
+	}
+	span.ended = true
+	span.End = time.Now()
+	span.mutex.Unlock()
+
+	if span.Sampled && exporter != nil {
+		exporter.Export(span)
+	}
+}
+
+// Traceparent formats the span as a W3C traceparent header value.
+func (span *Span) Traceparent() string {
+	flags := "00"
+	if span.Sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(span.TraceID[:]) + "-" + hex.EncodeToString(span.SpanID[:]) + "-" + flags
+}
+
+// Inject adds the traceparent header of the current span of the context to the header.
+func Inject(ctx context.Context, header http.Header) {
+	if span := FromContext(ctx); span != nil {
+		header.Set(TraceparentHeader, span.Traceparent())
+	}
+}
+
+// Extract parses a W3C traceparent header value into a remote parent span. False is returned if the value is invalid.
+func Extract(traceparent string) (*Span, bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return nil, false
+	}
+
+	span := &Span{}
+	if _, err := hex.Decode(span.TraceID[:], []byte(parts[1])); err != nil || span.TraceID == (TraceID{}) {
+		return nil, false
+	}
+	if _, err := hex.Decode(span.SpanID[:], []byte(parts[2])); err != nil || span.SpanID == (SpanID{}) {
+		return nil, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return nil, false
+	}
+	span.Sampled = flags[0]&1 == 1
+	return span, true
+}
+
+// Handler starts a server span for each request, continuing the trace of the inbound traceparent header if present.
+func Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		if parent, ok := Extract(req.Header.Get(TraceparentHeader)); ok {
+			ctx = context.WithValue(ctx, spanKey{}, parent)
+		}
+
+		ctx, span := StartSpan(ctx, req.Method+" "+req.URL.Path, KindServer)
+		span.SetAttribute("http.method", req.Method)
+		span.SetAttribute("http.target", req.URL.RequestURI())
+		span.SetAttribute("request_id", req.Header.Get("X-Request-Id"))
+		defer span.Finish()
+
+		rc := &statusCapture{ResponseWriter: w, statusCode: http.StatusOK}
+		h.ServeHTTP(rc, req.WithContext(ctx))
+
+		span.SetAttribute("http.status_code", rc.statusCode)
+		if rc.statusCode >= 500 {
+			span.SetError(fmt.Errorf("%d %s", rc.statusCode, http.StatusText(rc.statusCode)))
+		}
+	})
+}
+
+type statusCapture struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (s *statusCapture) WriteHeader(status int) {
+	s.statusCode = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func newTraceID() (id TraceID) {
+	rand.Read(id[:])
+	return
+}
+
+func newSpanID() (id SpanID) {
+	rand.Read(id[:])
+	return
+}
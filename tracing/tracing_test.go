@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// recordingExporter holds every span exported.
+type recordingExporter struct {
+	spans []*Span
+}
+
+func (e *recordingExporter) Export(span *Span) {
+	e.spans = append(e.spans, span)
+}
+
+func TestExtract(t *testing.T) {
+
+	Convey("Should parse a valid traceparent header.", t, func() {
+		span, ok := Extract(traceparent)
+
+		So(ok, ShouldBeTrue)
+		So(span.Sampled, ShouldBeTrue)
+		So(span.Traceparent(), ShouldEqual, traceparent)
+	})
+
+	Convey("Should reject invalid traceparent headers.", t, func() {
+		for _, value := range []string{
+			"",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+			"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+			"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01",
+		} {
+			_, ok := Extract(value)
+			So(ok, ShouldBeFalse)
+		}
+	})
+}
+
+func TestHandler(t *testing.T) {
+	exporter := &recordingExporter{}
+	SetExporter(exporter)
+	defer SetExporter(nil)
+
+	Convey("Should continue the inbound trace and propagate it to child spans.", t, func() {
+		var outbound http.Header
+		handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx, span := StartSpan(req.Context(), "zebedee GET /data", KindClient)
+			outbound = http.Header{}
+			Inject(ctx, outbound)
+			span.Finish()
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+
+		req := httptest.NewRequest("GET", "/economy", nil)
+		req.Header.Set(TraceparentHeader, traceparent)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		So(len(exporter.spans), ShouldEqual, 2)
+		client, server := exporter.spans[0], exporter.spans[1]
+		parent, _ := Extract(traceparent)
+
+		So(server.TraceID, ShouldEqual, parent.TraceID)
+		So(server.ParentSpanID, ShouldEqual, parent.SpanID)
+		So(server.Attributes()["http.status_code"], ShouldEqual, "502")
+		So(server.Error(), ShouldNotBeEmpty)
+		So(client.ParentSpanID, ShouldEqual, server.SpanID)
+
+		propagated, ok := Extract(outbound.Get(TraceparentHeader))
+		So(ok, ShouldBeTrue)
+		So(propagated.TraceID, ShouldEqual, parent.TraceID)
+		So(propagated.SpanID, ShouldEqual, client.SpanID)
+	})
+}
+
+func TestOTLPExporter(t *testing.T) {
+
+	Convey("Should send queued spans to the collector on shutdown.", t, func() {
+		var path string
+		var received otlpRequest
+		collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			path = req.URL.Path
+			body, _ := ioutil.ReadAll(req.Body)
+			json.Unmarshal(body, &received)
+		}))
+		defer collector.Close()
+
+		exporter := NewOTLPExporter(collector.URL, "dp-content-resolver", time.Minute)
+		exporter.Start()
+
+		_, span := StartSpan(context.Background(), "content.resolve", KindInternal)
+		span.SetAttribute("uri", "/")
+		span.Finish()
+		exporter.Export(span)
+		exporter.Shutdown(context.Background())
+
+		So(path, ShouldEqual, "/v1/traces")
+		So(len(received.ResourceSpans), ShouldEqual, 1)
+		spans := received.ResourceSpans[0].ScopeSpans[0].Spans
+		So(len(spans), ShouldEqual, 1)
+		So(spans[0].Name, ShouldEqual, "content.resolve")
+		So(spans[0].Attributes[0].Value.StringValue, ShouldEqual, "/")
+		So(spans[0].ParentSpanID, ShouldBeEmpty)
+	})
+}
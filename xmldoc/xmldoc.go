@@ -0,0 +1,19 @@
+// Package xmldoc writes the XML documents served by the resolver, such as sitemaps and feeds.
+package xmldoc
+
+import (
+	"bytes"
+	"encoding/xml"
+)
+
+// Marshal returns the value encoded as an indented XML document, or an error if the value cannot be encoded.
+func Marshal(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,28 @@
+package xmldoc
+
+import (
+	"encoding/xml"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMarshal(t *testing.T) {
+
+	Convey("Should encode the value as an indented XML document.", t, func() {
+		type item struct {
+			XMLName xml.Name `xml:"item"`
+			Title   string   `xml:"title"`
+		}
+
+		data, err := Marshal(item{Title: "Economy"})
+		So(err, ShouldBeNil)
+		So(string(data), ShouldEqual, xml.Header+"<item>\n  <title>Economy</title>\n</item>")
+	})
+
+	Convey("Should return an error if the value cannot be encoded.", t, func() {
+		data, err := Marshal(map[string]string{"title": "Economy"})
+		So(err, ShouldNotBeNil)
+		So(data, ShouldBeNil)
+	})
+}
@@ -0,0 +1,101 @@
+package zebedee
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxCacheEntries bounds the memory used by the response cache.
+const maxCacheEntries = 10000
+
+// CacheStats describes the state of the Zebedee response cache.
+type CacheStats struct {
+	Enabled bool   `json:"enabled"`
+	TTL     string `json:"ttl"`
+	Entries int    `json:"entries"`
+	Hits    int64  `json:"hits"`
+	Misses  int64  `json:"misses"`
+}
+
+type noCacheKey struct{}
+
+// WithoutCache returns a context for requests that must always be sent to Zebedee rather than served from the cache.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// cacheFor returns the cache to use for a request with the given context, or nil if it should not be cached.
+func (zebedee *Client) cacheFor(ctx context.Context) *responseCache {
+	if skip, _ := ctx.Value(noCacheKey{}).(bool); skip {
+		return nil
+	}
+	return zebedee.cache
+}
+
+type cachedResponse struct {
+	body     []byte
+	pageType string
+	expires  time.Time
+}
+
+// responseCache holds successful Zebedee responses for a fixed time, keyed on the request URL.
+type responseCache struct {
+	ttl     time.Duration
+	mutex   sync.RWMutex
+	entries map[string]cachedResponse
+	hits    int64
+	misses  int64
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{ttl: ttl, entries: make(map[string]cachedResponse)}
+}
+
+func (cache *responseCache) get(key string) (cachedResponse, bool) {
+	cache.mutex.RLock()
+	entry, ok := cache.entries[key]
+	cache.mutex.RUnlock()
+
+	if !ok || time.Now().After(entry.expires) {
+		atomic.AddInt64(&cache.misses, 1)
+		cacheLookups.Inc("miss")
+		return cachedResponse{}, false
+	}
+	atomic.AddInt64(&cache.hits, 1)
+	cacheLookups.Inc("hit")
+	return entry, true
+}
+
+func (cache *responseCache) set(key string, body []byte, pageType string) {
+	now := time.Now()
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if len(cache.entries) >= maxCacheEntries {
+		for k, entry := range cache.entries {
+			if now.After(entry.expires) {
+				delete(cache.entries, k)
+			}
+		}
+		if len(cache.entries) >= maxCacheEntries {
+			return
+		}
+	}
+	cache.entries[key] = cachedResponse{body: body, pageType: pageType, expires: now.Add(cache.ttl)}
+}
+
+func (cache *responseCache) stats() CacheStats {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+
+	return CacheStats{
+		Enabled: true,
+		TTL:     cache.ttl.String(),
+		Entries: len(cache.entries),
+		Hits:    atomic.LoadInt64(&cache.hits),
+		Misses:  atomic.LoadInt64(&cache.misses),
+	}
+}
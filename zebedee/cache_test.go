@@ -0,0 +1,33 @@
+package zebedee
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResponseCache(t *testing.T) {
+
+	Convey("Should return cached responses until they expire.", t, func() {
+		cache := newResponseCache(time.Minute)
+		cache.set("/data?uri=%2F", []byte("{}"), HomePage)
+
+		cached, ok := cache.get("/data?uri=%2F")
+		So(ok, ShouldBeTrue)
+		So(string(cached.body), ShouldEqual, "{}")
+		So(cached.pageType, ShouldEqual, HomePage)
+
+		_, ok = cache.get("/data?uri=%2Feconomy")
+		So(ok, ShouldBeFalse)
+
+		cache.entries["/data?uri=%2F"] = cachedResponse{expires: time.Now().Add(-time.Second)}
+		_, ok = cache.get("/data?uri=%2F")
+		So(ok, ShouldBeFalse)
+
+		stats := cache.stats()
+		So(stats.Hits, ShouldEqual, 1)
+		So(stats.Misses, ShouldEqual, 2)
+		So(stats.Entries, ShouldEqual, 1)
+	})
+}
@@ -1,6 +1,7 @@
 package zebedee
 
 import (
+	"context"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -10,6 +11,7 @@ import (
 
 	"encoding/json"
 	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/tracing"
 	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
 	"github.com/ONSdigital/go-ns/common"
 	"github.com/ONSdigital/go-ns/log"
@@ -36,6 +38,7 @@ type httpClient interface {
 type Client struct {
 	httpClient httpClient
 	url        string
+	cache      *responseCache
 }
 
 type parameter struct {
@@ -51,22 +54,64 @@ var resReader responseBodyReader = ioutil.ReadAll
 // CreateClient will create a new ZebedeeHTTPClient for the given url and timeout.
 func CreateClient(timeout time.Duration, zebedeeURL string) *Client {
 	return &Client{
-		&http.Client{
+		httpClient: &http.Client{
 			Timeout: timeout,
 		},
-		zebedeeURL}
+		url: zebedeeURL,
+	}
+}
+
+// EnableCache caches successful responses from Zebedee for the given time. Responses are not cached if the ttl is not
+// positive.
+func (zebedee *Client) EnableCache(ttl time.Duration) *Client {
+	if ttl > 0 {
+		zebedee.cache = newResponseCache(ttl)
+	}
+	return zebedee
+}
+
+// CacheStats returns the state of the response cache.
+func (zebedee *Client) CacheStats() CacheStats {
+	if zebedee.cache == nil {
+		return CacheStats{}
+	}
+	return zebedee.cache.stats()
 }
 
 // GetData will call Zebedee and return the data it provides in a []byte
-func (zebedee *Client) GetData(uri string, requestContextID string) (data []byte, pageType string, err *common.ONSError) {
+func (zebedee *Client) GetData(ctx context.Context, uri string, requestContextID string) (data []byte, pageType string, err *common.ONSError) {
+	recordDependency(ctx, uri)
+	request, error := zebedee.buildGetRequest(ctx, dataAPI, requestContextID, []parameter{{name: uriParam, value: uri}})
+	if error != nil {
+		return data, pageType, errorWithReqContextID(error, "error creating zebedee request.", requestContextID)
+	}
+
+	if shared := sharedFor(ctx); shared != nil {
+		return shared.do(request.URL.String(), recordCall(ctx, dataAPI, request), func() ([]byte, string, *common.ONSError) {
+			return zebedee.getData(ctx, request, requestContextID)
+		})
+	}
+	return zebedee.getData(ctx, request, requestContextID)
+}
+
+// getData sends the request to the data endpoint, or returns the cached response.
+func (zebedee *Client) getData(ctx context.Context, request *http.Request, requestContextID string) (data []byte, pageType string, err *common.ONSError) {
 	var response *http.Response
+	var error error
 
-	request, error := zebedee.buildGetRequest(dataAPI, requestContextID, []parameter{{name: uriParam, value: uri}})
-	if err != nil {
-		return data, pageType, errorWithReqContextID(error, "error creating zebedee request.", requestContextID)
+	call := recordCall(ctx, dataAPI, request)
+	defer func() { call.finish(len(data), err) }()
+
+	cache := zebedee.cacheFor(ctx)
+	if cache != nil {
+		if cached, ok := cache.get(request.URL.String()); ok {
+			call.cacheHit()
+			return cached.body, cached.pageType, nil
+		}
 	}
 
-	response, error = zebedee.httpClient.Do(request)
+	response, error = zebedee.do(dataAPI, request)
+	call.status(response)
 
 	if error != nil {
 		return data, pageType, errorWithReqContextID(error, zebedeeGetError, requestContextID)
@@ -93,17 +138,22 @@ func (zebedee *Client) GetData(uri string, requestContextID string) (data []byte
 
 	pageType = response.Header.Get(pageTypeHeader)
 	log.Debug("Identified page type", log.Data{"page type": pageType})
+
+	if cache != nil {
+		cache.set(request.URL.String(), data, pageType)
+	}
 	return
 }
 
 // GetTaxonomy gets the taxonomy structure of the website from Zebedee
-func (zebedee *Client) GetTaxonomy(uri string, depth int, requestContextID string) ([]zebedeeModel.ContentNode, *common.ONSError) {
+func (zebedee *Client) GetTaxonomy(ctx context.Context, uri string, depth int, requestContextID string) ([]zebedeeModel.ContentNode, *common.ONSError) {
+	recordDependency(ctx, uri)
 	var zebedeeContentNodeList []zebedeeModel.ContentNode
 	params := []parameter{
 		{name: uriParam, value: uri},
 		{name: "depth", value: strconv.Itoa(depth)},
 	}
-	zebedeeBytes, err := zebedee.get(taxonomyAPI, requestContextID, params)
+	zebedeeBytes, err := zebedee.get(ctx, taxonomyAPI, requestContextID, params)
 
 	if err != nil {
 		return zebedeeContentNodeList, err
@@ -117,9 +167,10 @@ func (zebedee *Client) GetTaxonomy(uri string, depth int, requestContextID strin
 }
 
 // GetParents gets the breadcrumb for the given url.
-func (zebedee *Client) GetParents(uri string, requestContextID string) ([]zebedeeModel.ContentNode, *common.ONSError) {
+func (zebedee *Client) GetParents(ctx context.Context, uri string, requestContextID string) ([]zebedeeModel.ContentNode, *common.ONSError) {
+	recordDependency(ctx, uri)
 	var zebedeeContentNodes []zebedeeModel.ContentNode
-	zebedeeBytes, err := zebedee.get(breadcrumbAPI, requestContextID, []parameter{{name: uriParam, value: uri}})
+	zebedeeBytes, err := zebedee.get(ctx, breadcrumbAPI, requestContextID, []parameter{{name: uriParam, value: uri}})
 
 	if err != nil {
 		return zebedeeContentNodes, err
@@ -127,15 +178,17 @@ func (zebedee *Client) GetParents(uri string, requestContextID string) ([]zebede
 
 	unmarshallErr := json.Unmarshal(zebedeeBytes, &zebedeeContentNodes)
 	if unmarshallErr != nil {
-		return zebedeeContentNodes, errorWithReqContextID(err, "error unmarshalling zebedee contentNodes", requestContextID)
+		return zebedeeContentNodes, errorWithReqContextID(unmarshallErr, "error unmarshalling zebedee contentNodes", requestContextID)
 	}
+	recordNodeDependencies(ctx, zebedeeContentNodes)
 	return zebedeeContentNodes, nil
 }
 
 // GetTimeSeries - get timeseries data.json from Zebedee.
-func (zebedee *Client) GetTimeSeries(uri string, requestContextID string) (*zebedeeModel.TimeseriesPage, *common.ONSError) {
+func (zebedee *Client) GetTimeSeries(ctx context.Context, uri string, requestContextID string) (*zebedeeModel.TimeseriesPage, *common.ONSError) {
+	recordDependency(ctx, uri)
 	params := []parameter{{name: uriParam, value: uri}, {name: "series"}}
-	zebedeeBytes, err := zebedee.get(dataAPI, requestContextID, params)
+	zebedeeBytes, err := zebedee.get(ctx, dataAPI, requestContextID, params)
 
 	if err != nil {
 		return nil, err
@@ -150,24 +203,49 @@ func (zebedee *Client) GetTimeSeries(uri string, requestContextID string) (*zebe
 }
 
 // Perform a HTTP GET request to zebedee for the specified uri & parameters.
-func (zebedee *Client) get(path string, requestContextID string, params []parameter) ([]byte, *common.ONSError) {
-	request, err := zebedee.buildGetRequest(path, requestContextID, params)
+func (zebedee *Client) get(ctx context.Context, path string, requestContextID string, params []parameter) ([]byte, *common.ONSError) {
+	request, err := zebedee.buildGetRequest(ctx, path, requestContextID, params)
 	if err != nil {
 		return nil, errorWithReqContextID(err, "error creating zebedee request", requestContextID)
 	}
 
+	if shared := sharedFor(ctx); shared != nil {
+		body, _, onsErr := shared.do(request.URL.String(), recordCall(ctx, path, request), func() ([]byte, string, *common.ONSError) {
+			body, onsErr := zebedee.fetch(ctx, path, request, requestContextID)
+			return body, "", onsErr
+		})
+		return body, onsErr
+	}
+	return zebedee.fetch(ctx, path, request, requestContextID)
+}
+
+// fetch sends the request to the endpoint at path, or returns the cached response.
+func (zebedee *Client) fetch(ctx context.Context, path string, request *http.Request, requestContextID string) (body []byte, onsErr *common.ONSError) {
+	var err error
+
+	call := recordCall(ctx, path, request)
+	defer func() { call.finish(len(body), onsErr) }()
+
+	cache := zebedee.cacheFor(ctx)
+	if cache != nil {
+		if cached, ok := cache.get(request.URL.String()); ok {
+			call.cacheHit()
+			return cached.body, nil
+		}
+	}
+
 	log.Debug("Zebedee Client HTTP GET", log.Data{
 		"uri":                 request.URL.Path,
 		"method":              "GET",
 		requestContextIDParam: requestContextID,
 		"query":               request.URL.RawQuery,
 	})
-	response, err := zebedee.httpClient.Do(request)
-	defer response.Body.Close()
-
+	response, err := zebedee.do(path, request)
+	call.status(response)
 	if err != nil {
 		return nil, errorWithReqContextID(err, "error performing zebedee request", requestContextID)
 	}
+	defer response.Body.Close()
 
 	if response.StatusCode != 200 {
 		onsError := errorWithReqContextID(errors.New("Unexpected Response status code"), incorrectStatusCodeErrDesc, requestContextID)
@@ -178,17 +256,48 @@ func (zebedee *Client) get(path string, requestContextID string, params []parame
 		return nil, onsError
 	}
 
-	body, err := resReader(response.Body)
+	body, err = resReader(response.Body)
 	if err != nil {
 		return nil, errorWithReqContextID(err, "error reading zebedee response body", requestContextID)
 	}
+
+	if cache != nil {
+		cache.set(request.URL.String(), body, "")
+	}
 	return body, nil
 }
 
+// do sends the request to Zebedee, recording its duration by endpoint and response status.
+func (zebedee *Client) do(endpoint string, request *http.Request) (*http.Response, error) {
+	ctx, span := tracing.StartSpan(request.Context(), "zebedee GET "+endpoint, tracing.KindClient)
+	span.SetAttribute("zebedee.endpoint", endpoint)
+	span.SetAttribute("zebedee.uri", request.URL.Query().Get(uriParam))
+	span.SetAttribute(requestContextIDParam, request.Header.Get(requests.RequestIDHeaderParam))
+	defer span.Finish()
+
+	request = request.WithContext(ctx)
+	tracing.Inject(ctx, request.Header)
+
+	start := time.Now()
+	response, err := zebedee.httpClient.Do(request)
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(response.StatusCode)
+		span.SetAttribute("http.status_code", response.StatusCode)
+		if response.StatusCode != http.StatusOK {
+			span.SetError(errors.New("unexpected response status code " + status))
+		}
+	}
+	span.SetError(err)
+	requestDuration.Observe(time.Since(start).Seconds(), endpoint, status)
+	return response, err
+}
+
 // buildGetRequest builds a new http GET Request using the uri and parameters provided and adds the request context Id as
-// a header to the new request.
-func (zebedee *Client) buildGetRequest(url string, requestContextID string, params []parameter) (*http.Request, error) {
-	request, err := http.NewRequest("GET", zebedee.url+url, nil)
+// a header to the new request. The request is cancelled when the context is done.
+func (zebedee *Client) buildGetRequest(ctx context.Context, url string, requestContextID string, params []parameter) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", zebedee.url+url, nil)
 	if err != nil {
 		return nil, err
 	}
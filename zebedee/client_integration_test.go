@@ -0,0 +1,81 @@
+package zebedee_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	"github.com/ONSdigital/dp-content-resolver/zebedee/zebedeetest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const timeseriesURI = "/economy/inflationandpriceindices/timeseries/d7g7"
+
+func TestClientAgainstFakeZebedee(t *testing.T) {
+	server := zebedeetest.NewServer("testdata/content")
+	defer server.Close()
+
+	client := zebedee.CreateClient(time.Second, server.URL)
+	ctx := context.Background()
+
+	Convey("Should get the data and page type of a page.", t, func() {
+		data, pageType, err := client.GetData(ctx, "/economy", "abc123.1")
+
+		So(err, ShouldBeNil)
+		So(pageType, ShouldEqual, zebedee.TaxonomyLandingPage)
+		So(string(data), ShouldContainSubstring, `"Economy"`)
+		So(server.Requests()[0], ShouldResemble, zebedeetest.Request{
+			Endpoint:         zebedeetest.DataEndpoint,
+			URI:              "/economy",
+			Query:            "uri=%2Feconomy",
+			RequestContextID: "abc123.1",
+		})
+	})
+
+	Convey("Should get the taxonomy, parents and timeseries.", t, func() {
+		taxonomy, err := client.GetTaxonomy(ctx, "/", 1, "abc123.2")
+		So(err, ShouldBeNil)
+		So(len(taxonomy), ShouldEqual, 2)
+
+		parents, err := client.GetParents(ctx, timeseriesURI, "abc123.3")
+		So(err, ShouldBeNil)
+		So(len(parents), ShouldEqual, 3)
+
+		page, err := client.GetTimeSeries(ctx, timeseriesURI, "abc123.4")
+		So(err, ShouldBeNil)
+		So(len(page.Series), ShouldEqual, 3)
+	})
+
+	Convey("Should return errors for scripted failures.", t, func() {
+		defer server.Reset()
+
+		server.Script(zebedeetest.DataEndpoint, "/", zebedeetest.Behaviour{Status: 401, Times: 1})
+		_, _, err := client.GetData(ctx, "/", "abc123.5")
+		So(err.RootError, ShouldEqual, zebedee.ErrUnauthorised)
+
+		server.Script(zebedeetest.TaxonomyEndpoint, "", zebedeetest.Behaviour{Status: 503})
+		_, err = client.GetTaxonomy(ctx, "/", 1, "abc123.6")
+		So(err.Parameters["actualStatusCode"], ShouldEqual, 503)
+
+		server.Script(zebedeetest.ParentsEndpoint, "", zebedeetest.Behaviour{Drop: true})
+		_, err = client.GetParents(ctx, "/", "abc123.7")
+		So(err, ShouldNotBeNil)
+
+		_, _, err = client.GetData(ctx, "/business", "abc123.8")
+		So(err.Parameters["actualStatusCode"], ShouldEqual, 404)
+	})
+
+	Convey("Should time out slow responses.", t, func() {
+		defer server.Reset()
+		server.Script("", "", zebedeetest.Behaviour{Latency: time.Second})
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond*50)
+		defer cancel()
+
+		start := time.Now()
+		_, _, err := client.GetData(timeoutCtx, "/", "abc123.9")
+		So(err, ShouldNotBeNil)
+		So(time.Since(start), ShouldBeLessThan, time.Second)
+	})
+}
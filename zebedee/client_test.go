@@ -2,6 +2,7 @@ package zebedee
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"github.com/ONSdigital/dp-content-resolver/requests"
@@ -48,11 +49,13 @@ func ReadBodyMock(io.Reader) ([]byte, error) {
 }
 
 func TestGetData(t *testing.T) {
+	defer func() { resReader = ioutil.ReadAll }()
+
 	// create stub http client for test
 	testHTTPClient := &testClient{}
 
 	// inject it into an instance of zebedeeHTTPClient
-	zebedeeClient := Client{testHTTPClient, baseZebedeeURL}
+	zebedeeClient := Client{httpClient: testHTTPClient, url: baseZebedeeURL}
 
 	Convey("Should return empty data, page type and correct error if zebedee.get data fails.", t, func() {
 
@@ -62,7 +65,7 @@ func TestGetData(t *testing.T) {
 		pageTypeStub = ""
 		onsErrorStub = common.NewONSError(errorStub, zebedeeGetError)
 		onsErrorStub.AddParameter(requestContextIDParam, requestContextID)
-		data, pageType, err := zebedeeClient.GetData("/", requestContextID)
+		data, pageType, err := zebedeeClient.GetData(context.Background(), "/", requestContextID)
 
 		ShouldEqual(data, dataStub)
 		ShouldEqual(pageType, pageTypeStub)
@@ -85,7 +88,7 @@ func TestGetData(t *testing.T) {
 		pageTypeStub = ""
 
 		// Run test
-		data, pageType, err := zebedeeClient.GetData("/", requestContextID)
+		data, pageType, err := zebedeeClient.GetData(context.Background(), "/", requestContextID)
 
 		// assert results.
 		So(err, ShouldResemble, onsErrorStub)
@@ -110,7 +113,7 @@ func TestGetData(t *testing.T) {
 		responseBodyReadErrStub = rootErr
 		responseBodyBytesStub = []byte("")
 
-		data, pageType, err := zebedeeClient.GetData("/", requestContextID)
+		data, pageType, err := zebedeeClient.GetData(context.Background(), "/", requestContextID)
 
 		So(err, ShouldResemble, onsErrorStub)
 		So(data, ShouldResemble, dataStub)
@@ -134,7 +137,7 @@ func TestGetData(t *testing.T) {
 		responseBodyReadErrStub = nil
 		responseBodyBytesStub = []byte(body)
 
-		data, pageType, err := zebedeeClient.GetData("/", requestContextID)
+		data, pageType, err := zebedeeClient.GetData(context.Background(), "/", requestContextID)
 
 		So(err, ShouldResemble, onsErrorStub)
 		So(data, ShouldResemble, dataStub)
@@ -145,12 +148,12 @@ func TestGetData(t *testing.T) {
 func TestBuildRequest(t *testing.T) {
 	// create stub http client for test
 	testHTTPClient := &testClient{}
-	zebedeeClient := Client{testHTTPClient, zebedeeURI}
+	zebedeeClient := Client{httpClient: testHTTPClient, url: zebedeeURI}
 
 	Convey("Should build the expected request for the given parameters.", t, func() {
 		uriParameter := "/someURL"
 		params := []parameter{{"name1", "value1"}}
-		actual, err := zebedeeClient.buildGetRequest(uriParameter, requestContextID, params)
+		actual, err := zebedeeClient.buildGetRequest(context.Background(), uriParameter, requestContextID, params)
 
 		So(err, ShouldBeEmpty)
 		So(actual.URL.Path, ShouldEqual, zebedeeURI+uriParameter)
@@ -160,8 +163,10 @@ func TestBuildRequest(t *testing.T) {
 }
 
 func TestGetParents(t *testing.T) {
+	defer func() { resReader = ioutil.ReadAll }()
+
 	testHTTPClient := &testClient{}
-	zebedeeClient := Client{testHTTPClient, zebedeeURI}
+	zebedeeClient := Client{httpClient: testHTTPClient, url: zebedeeURI}
 
 	Convey("Should return parents for 200 response status & valid response body.", t, func() {
 		// Set a mock for reading the response body.
@@ -182,7 +187,7 @@ func TestGetParents(t *testing.T) {
 		responseBodyReadErrStub = nil
 		responseBodyBytesStub = zebedeeBytes
 
-		result, err := zebedeeClient.GetParents("/", requestContextID)
+		result, err := zebedeeClient.GetParents(context.Background(), "/", requestContextID)
 		So(result, ShouldResemble, zebedeeParents)
 		So(len(result), ShouldEqual, len(zebedeeParents))
 		So(err, ShouldBeNil)
@@ -209,7 +214,7 @@ func TestGetParents(t *testing.T) {
 		responseBodyReadErrStub = nil
 		responseBodyBytesStub = []byte(responseBody)
 
-		result, err := zebedeeClient.GetParents("/", requestContextID)
+		result, err := zebedeeClient.GetParents(context.Background(), "/", requestContextID)
 		So(result, ShouldResemble, expectedParents)
 		So(err, ShouldResemble, onsErrorStub)
 	})
@@ -230,9 +235,10 @@ func TestGetParents(t *testing.T) {
 		responseBodyReadErrStub = nil
 		responseBodyBytesStub = []byte(responseBody)
 
-		result, err := zebedeeClient.GetParents("/", requestContextID)
+		result, err := zebedeeClient.GetParents(context.Background(), "/", requestContextID)
 		So(result, ShouldResemble, expectedParents)
 		So(err.Parameters, ShouldResemble, onsErrorStub.Parameters)
+		So(err.RootError, ShouldHaveSameTypeAs, &json.SyntaxError{})
 	})
 }
 
@@ -0,0 +1,60 @@
+package zebedee
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+)
+
+// Dependencies records the uris of the Zebedee content requested using a context, so that caches of the pages built
+// from it can be purged when it is published. It is safe for concurrent use.
+type Dependencies struct {
+	mutex sync.Mutex
+	uris  map[string]bool
+}
+
+type dependenciesKey struct{}
+
+// WithDependencies returns a context that records the uri of every Zebedee request made using it in the returned
+// Dependencies, whether or not the request succeeds or is served from the cache, and the uri of each parent returned.
+func WithDependencies(ctx context.Context) (context.Context, *Dependencies) {
+	dependencies := &Dependencies{uris: make(map[string]bool)}
+	return context.WithValue(ctx, dependenciesKey{}, dependencies), dependencies
+}
+
+// DependenciesFrom returns the dependencies recorded for the context, or nil if they are not being recorded.
+func DependenciesFrom(ctx context.Context) *Dependencies {
+	dependencies, _ := ctx.Value(dependenciesKey{}).(*Dependencies)
+	return dependencies
+}
+
+// URIs returns the distinct uris recorded so far, in order.
+func (dependencies *Dependencies) URIs() []string {
+	dependencies.mutex.Lock()
+	uris := make([]string, 0, len(dependencies.uris))
+	for uri := range dependencies.uris {
+		uris = append(uris, uri)
+	}
+	dependencies.mutex.Unlock()
+
+	sort.Strings(uris)
+	return uris
+}
+
+// recordNodeDependencies records the uri of each of the nodes, as pages built from them include their titles.
+func recordNodeDependencies(ctx context.Context, nodes []zebedeeModel.ContentNode) {
+	for _, node := range nodes {
+		recordDependency(ctx, node.URI)
+	}
+}
+
+// recordDependency records the uri if the context is recording dependencies.
+func recordDependency(ctx context.Context, uri string) {
+	if dependencies := DependenciesFrom(ctx); dependencies != nil {
+		dependencies.mutex.Lock()
+		dependencies.uris[uri] = true
+		dependencies.mutex.Unlock()
+	}
+}
@@ -0,0 +1,66 @@
+package zebedee
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDependencies(t *testing.T) {
+
+	Convey("Should record the distinct uris requested using the context, in order.", t, func() {
+		ctx, dependencies := WithDependencies(context.Background())
+		fs := NewFileSystem(testContentDir)
+
+		fs.GetData(ctx, "/economy", "abc")
+		fs.GetTaxonomy(ctx, "/", 2, "abc")
+		fs.GetParents(ctx, "/economy", "abc")
+		fs.GetData(ctx, "/business", "abc")
+
+		So(dependencies.URIs(), ShouldResemble, []string{"/", "/business", "/economy"})
+	})
+
+	Convey("Should record the uri of each parent returned.", t, func() {
+		ctx, dependencies := WithDependencies(context.Background())
+		NewFileSystem(testContentDir).GetParents(ctx, "/economy/inflationandpriceindices/timeseries/d7g7", "abc")
+		So(dependencies.URIs(), ShouldResemble, []string{"/", "/economy", "/economy/inflationandpriceindices",
+			"/economy/inflationandpriceindices/timeseries/d7g7"})
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(`[{"uri": "/"}, {"uri": "/economy"}]`))
+		}))
+		defer server.Close()
+
+		ctx, dependencies = WithDependencies(context.Background())
+		CreateClient(time.Second, server.URL).GetParents(ctx, "/economy/inflationandpriceindices", "abc")
+		So(dependencies.URIs(), ShouldResemble, []string{"/", "/economy", "/economy/inflationandpriceindices"})
+	})
+
+	Convey("Should record the uris requested from Zebedee, including those shared with other requests.", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte("[]"))
+		}))
+		defer server.Close()
+		client := CreateClient(time.Second, server.URL)
+
+		shared := WithSharedResponses(context.Background())
+		first, firstDependencies := WithDependencies(shared)
+		second, secondDependencies := WithDependencies(shared)
+
+		client.GetParents(first, "/economy", "abc")
+		client.GetParents(second, "/economy", "abc")
+		client.GetTaxonomy(second, "/", 2, "abc")
+
+		So(firstDependencies.URIs(), ShouldResemble, []string{"/economy"})
+		So(secondDependencies.URIs(), ShouldResemble, []string{"/", "/economy"})
+	})
+
+	Convey("Should not record uris for contexts without dependencies.", t, func() {
+		So(DependenciesFrom(context.Background()), ShouldBeNil)
+		recordDependency(context.Background(), "/economy")
+	})
+}
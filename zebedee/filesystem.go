@@ -0,0 +1,233 @@
+package zebedee
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+	"github.com/ONSdigital/go-ns/common"
+)
+
+// dataFile is the name of the file holding the content of each page in a Zebedee content directory.
+const dataFile = "data.json"
+
+// ErrNotFound is returned when the requested content does not exist.
+var ErrNotFound = errors.New("content not found")
+
+// FileSystem is a Service that reads content from a Zebedee content directory on disk rather than calling Zebedee,
+// so pages can be resolved without a running Zebedee. The content of each page is held in the data.json file of the
+// directory at its uri, e.g. economy/inflationandpriceindices/data.json.
+type FileSystem struct {
+	root string
+}
+
+// page holds the fields of a data.json file common to every page type.
+type page struct {
+	URI         string                       `json:"uri"`
+	Type        string                       `json:"type"`
+	Description zebedeeModel.PageDescription `json:"description"`
+}
+
+// NewFileSystem creates a new FileSystem reading content from the given Zebedee content directory.
+func NewFileSystem(root string) *FileSystem {
+	return &FileSystem{root: root}
+}
+
+// GetData reads the data.json of the page at the uri, returning it along with its page type.
+func (fs *FileSystem) GetData(ctx context.Context, uri string, requestContextID string) ([]byte, string, *common.ONSError) {
+	recordDependency(ctx, uri)
+
+	if err := ctx.Err(); err != nil {
+		return nil, "", errorWithReqContextID(err, zebedeeGetError, requestContextID)
+	}
+
+	data, err := fs.read(uri)
+	if err != nil {
+		return nil, "", fs.error(err, uri, requestContextID)
+	}
+
+	var content page
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, "", fs.error(err, uri, requestContextID)
+	}
+	return data, content.Type, nil
+}
+
+// GetTaxonomy returns the taxonomy beneath the uri to the given depth, derived from the taxonomy landing pages and
+// product pages in the content directory.
+func (fs *FileSystem) GetTaxonomy(ctx context.Context, uri string, depth int, requestContextID string) ([]zebedeeModel.ContentNode, *common.ONSError) {
+	recordDependency(ctx, uri)
+
+	if err := ctx.Err(); err != nil {
+		return nil, errorWithReqContextID(err, zebedeeGetError, requestContextID)
+	}
+
+	if _, err := fs.read(uri); err != nil {
+		return nil, fs.error(err, uri, requestContextID)
+	}
+
+	nodes, err := fs.taxonomy(uri, depth)
+	if err != nil {
+		return nil, fs.error(err, uri, requestContextID)
+	}
+	return nodes, nil
+}
+
+// GetParents returns the pages above the uri, starting with the homepage.
+func (fs *FileSystem) GetParents(ctx context.Context, uri string, requestContextID string) ([]zebedeeModel.ContentNode, *common.ONSError) {
+	recordDependency(ctx, uri)
+
+	if err := ctx.Err(); err != nil {
+		return nil, errorWithReqContextID(err, zebedeeGetError, requestContextID)
+	}
+
+	if _, err := fs.read(uri); err != nil {
+		return nil, fs.error(err, uri, requestContextID)
+	}
+
+	var parents []zebedeeModel.ContentNode
+	for parent := parentURI(uri); len(parent) > 0; parent = parentURI(parent) {
+		content, err := fs.page(parent)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fs.error(err, parent, requestContextID)
+		}
+		parents = append([]zebedeeModel.ContentNode{content.node()}, parents...)
+	}
+	recordNodeDependencies(ctx, parents)
+	return parents, nil
+}
+
+// GetTimeSeries reads the timeseries page at the uri. As Zebedee does for a series request, the series is set to the
+// most frequent of the monthly, quarterly and yearly data if the page does not hold one.
+func (fs *FileSystem) GetTimeSeries(ctx context.Context, uri string, requestContextID string) (*zebedeeModel.TimeseriesPage, *common.ONSError) {
+	data, _, onsErr := fs.GetData(ctx, uri, requestContextID)
+	if onsErr != nil {
+		return nil, onsErr
+	}
+
+	var timeSeriesPage *zebedeeModel.TimeseriesPage
+	if err := json.Unmarshal(data, &timeSeriesPage); err != nil {
+		return nil, errorWithReqContextID(err, "Error unmarshalling timeseries pages json.", requestContextID)
+	}
+
+	if len(timeSeriesPage.Series) == 0 {
+		for _, entries := range [][]zebedeeModel.TimeSeriesEntry{timeSeriesPage.Months, timeSeriesPage.Quarters, timeSeriesPage.Years} {
+			if len(entries) > 0 {
+				timeSeriesPage.Series = series(entries)
+				break
+			}
+		}
+	}
+	return timeSeriesPage, nil
+}
+
+// taxonomy returns the taxonomy nodes beneath the uri, recursing to the given depth.
+func (fs *FileSystem) taxonomy(uri string, depth int) ([]zebedeeModel.ContentNode, error) {
+	if depth <= 0 {
+		return nil, nil
+	}
+
+	dirs, err := ioutil.ReadDir(fs.filePath(uri))
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]zebedeeModel.ContentNode, 0)
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+
+		childURI := path.Join(uri, dir.Name())
+		content, err := fs.page(childURI)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if content.Type != TaxonomyLandingPage && content.Type != ProductPage {
+			continue
+		}
+
+		node := content.node()
+		if node.Children, err = fs.taxonomy(childURI, depth-1); err != nil {
+			return nil, err
+		}
+		if len(node.Children) == 0 {
+			node.Children = nil
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// page reads the common fields of the page at the uri.
+func (fs *FileSystem) page(uri string) (*page, error) {
+	data, err := fs.read(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	var content page
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	if len(content.URI) == 0 {
+		content.URI = uri
+	}
+	return &content, nil
+}
+
+// read returns the contents of the data.json file for the uri.
+func (fs *FileSystem) read(uri string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(fs.filePath(uri), dataFile))
+}
+
+// filePath returns the directory holding the content of the uri. The uri is cleaned so it cannot refer to anything
+// outside of the content directory.
+func (fs *FileSystem) filePath(uri string) string {
+	return filepath.Join(fs.root, filepath.FromSlash(path.Clean("/"+uri)))
+}
+
+// error returns the ONSError for a failure to read the content at the uri.
+func (fs *FileSystem) error(err error, uri string, requestContextID string) *common.ONSError {
+	if os.IsNotExist(err) {
+		err = ErrNotFound
+	}
+	onsErr := errorWithReqContextID(err, "error reading zebedee content", requestContextID)
+	onsErr.AddParameter("zebedeeURI", uri)
+	return onsErr
+}
+
+func (content *page) node() zebedeeModel.ContentNode {
+	return zebedeeModel.ContentNode{URI: content.URI, Description: content.Description, PageType: content.Type}
+}
+
+// parentURI returns the uri of the parent of the page at the uri, or an empty string for the homepage.
+func parentURI(uri string) string {
+	uri = path.Clean("/" + uri)
+	if uri == "/" {
+		return ""
+	}
+	return path.Dir(uri)
+}
+
+// series converts the entries of a timeseries frequency to the series format returned by Zebedee.
+func series(entries []zebedeeModel.TimeSeriesEntry) []zebedeeModel.TimeSeriesValue {
+	values := make([]zebedeeModel.TimeSeriesValue, 0, len(entries))
+	for _, entry := range entries {
+		y, _ := strconv.ParseFloat(entry.Value, 32)
+		values = append(values, zebedeeModel.TimeSeriesValue{Name: entry.Date, Y: float32(y), StringY: entry.Value})
+	}
+	return values
+}
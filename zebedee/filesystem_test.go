@@ -0,0 +1,75 @@
+package zebedee
+
+import (
+	"context"
+	"testing"
+
+	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testContentDir = "testdata/content"
+
+func TestFileSystem(t *testing.T) {
+	fs := NewFileSystem(testContentDir)
+	ctx := context.Background()
+
+	Convey("Should return the data and page type of a page.", t, func() {
+		data, pageType, err := fs.GetData(ctx, "/", requestContextID)
+
+		So(err, ShouldBeNil)
+		So(pageType, ShouldEqual, HomePage)
+		So(string(data), ShouldContainSubstring, `"sections"`)
+	})
+
+	Convey("Should return not found for missing content and uris outside the content directory.", t, func() {
+		for _, uri := range []string{"/business", "/../content"} {
+			_, _, err := fs.GetData(ctx, uri, requestContextID)
+			So(err.RootError, ShouldEqual, ErrNotFound)
+			So(err.Parameters[requestContextIDParam], ShouldEqual, requestContextID)
+		}
+	})
+
+	Convey("Should derive the taxonomy to the requested depth.", t, func() {
+		taxonomy, err := fs.GetTaxonomy(ctx, "/", 2, requestContextID)
+
+		So(err, ShouldBeNil)
+		So(len(taxonomy), ShouldEqual, 2)
+		So(taxonomy[0].URI, ShouldEqual, "/economy")
+		So(taxonomy[0].PageType, ShouldEqual, TaxonomyLandingPage)
+		So(len(taxonomy[0].Children), ShouldEqual, 2)
+		So(taxonomy[0].Children[0].Description.Title, ShouldEqual, "Gross Domestic Product (GDP)")
+		So(taxonomy[1].URI, ShouldEqual, "/employmentandlabourmarket")
+
+		taxonomy, err = fs.GetTaxonomy(ctx, "/", 1, requestContextID)
+		So(err, ShouldBeNil)
+		So(taxonomy[0].Children, ShouldBeNil)
+	})
+
+	Convey("Should derive the parents of a page from the homepage down.", t, func() {
+		parents, err := fs.GetParents(ctx, "/economy/inflationandpriceindices/timeseries/d7g7", requestContextID)
+
+		So(err, ShouldBeNil)
+		So(len(parents), ShouldEqual, 3)
+		So(parents[0].URI, ShouldEqual, "/")
+		So(parents[1].URI, ShouldEqual, "/economy")
+		So(parents[2].Description.Title, ShouldEqual, "Inflation and price indices")
+	})
+
+	Convey("Should set the timeseries series to its most frequent data.", t, func() {
+		page, err := fs.GetTimeSeries(ctx, "/economy/inflationandpriceindices/timeseries/d7g7", requestContextID)
+
+		So(err, ShouldBeNil)
+		So(page.Description.Number, ShouldEqual, "0.9")
+		So(len(page.Series), ShouldEqual, 3)
+		So(page.Series[2], ShouldResemble, zebedeeModel.TimeSeriesValue{Name: "2016 OCT", Y: 0.9, StringY: "0.9"})
+	})
+
+	Convey("Should return an error once the context is done.", t, func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, _, err := fs.GetData(cancelled, "/", requestContextID)
+		So(err.RootError, ShouldEqual, context.Canceled)
+	})
+}
@@ -0,0 +1,20 @@
+package zebedee
+
+import (
+	"github.com/ONSdigital/dp-content-resolver/metrics"
+)
+
+var requestDuration = metrics.NewHistogram("zebedee_request_duration_seconds",
+	"Duration of requests to Zebedee by endpoint and response status.", metrics.DefaultBuckets, "endpoint", "status")
+
+var cacheLookups = metrics.NewCounter("zebedee_cache_lookups_total",
+	"Zebedee response cache lookups by result.", "result")
+
+var _ = metrics.NewGaugeFunc("zebedee_cache_hit_ratio",
+	"Ratio of Zebedee response cache lookups that were hits.", func() float64 {
+		hits, misses := cacheLookups.Value("hit"), cacheLookups.Value("miss")
+		if hits+misses == 0 {
+			return 0
+		}
+		return hits / (hits + misses)
+	})
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"encoding/json"
+	"path"
+	"strings"
+)
+
+// PageLinks returns the uris of the pages on the site linked to in the Zebedee content of the page at the uri.
+func PageLinks(uri string, data []byte) []string {
+	var content interface{}
+	if json.Unmarshal(data, &content) != nil {
+		return nil
+	}
+
+	var pages []string
+	for _, link := range linkedURIs(content) {
+		if link = NormaliseURI(link); len(link) > 0 && link != uri {
+			pages = append(pages, link)
+		}
+	}
+	return pages
+}
+
+// linkedURIs returns the value of every uri field in the page content.
+func linkedURIs(content interface{}) []string {
+	var uris []string
+	switch value := content.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if uri, ok := child.(string); ok && key == "uri" {
+				uris = append(uris, uri)
+				continue
+			}
+			uris = append(uris, linkedURIs(child)...)
+		}
+	case []interface{}:
+		for _, child := range value {
+			uris = append(uris, linkedURIs(child)...)
+		}
+	}
+	return uris
+}
+
+// NormaliseURI returns the cleaned uri of a page on the site, or an empty string if the uri is not a page on the site,
+// such as an external link or a file download.
+func NormaliseURI(uri string) string {
+	if !strings.HasPrefix(uri, "/") || strings.HasPrefix(uri, "//") || strings.ContainsAny(uri, "?#") {
+		return ""
+	}
+	uri = path.Clean(uri)
+	if len(path.Ext(uri)) > 0 {
+		return ""
+	}
+	return uri
+}
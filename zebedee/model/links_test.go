@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNormaliseURI(t *testing.T) {
+
+	Convey("Should only keep pages on the site.", t, func() {
+		So(NormaliseURI("/economy/"), ShouldEqual, "/economy")
+		So(NormaliseURI("https://www.ons.gov.uk/economy"), ShouldBeEmpty)
+		So(NormaliseURI("//example.com"), ShouldBeEmpty)
+		So(NormaliseURI("/file.xls"), ShouldBeEmpty)
+		So(NormaliseURI("/search?q=cpi"), ShouldBeEmpty)
+	})
+}
+
+func TestPageLinks(t *testing.T) {
+
+	Convey("Should return the pages on the site linked to in the content.", t, func() {
+		data := []byte(`{"uri": "/economy", "sections": [{"statistics": {"uri": "/economy/gdp/"}}, {"uri": "https://example.com"}]}`)
+
+		So(PageLinks("/economy", data), ShouldResemble, []string{"/economy/gdp"})
+		So(PageLinks("/economy", []byte("not json")), ShouldBeEmpty)
+	})
+}
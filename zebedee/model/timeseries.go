@@ -5,7 +5,10 @@ type TimeseriesPage struct {
 	Type        string            `json:"type"`
 	URI         string            `json:"uri"`
 	Description PageDescription   `json:"description"`
-	Series      []TimeSeriesValue `json:series`
+	Series      []TimeSeriesValue `json:"series"`
+	Years       []TimeSeriesEntry `json:"years"`
+	Quarters    []TimeSeriesEntry `json:"quarters"`
+	Months      []TimeSeriesEntry `json:"months"`
 }
 
 // TimeSeriesValue represents an individual time series entry.
@@ -14,3 +17,14 @@ type TimeSeriesValue struct {
 	Y       float32 `json:"y"`
 	StringY string  `json:"stringY"`
 }
+
+// TimeSeriesEntry represents an individual entry of the yearly, quarterly or monthly time series data. These are only
+// present when the full time series page is requested.
+type TimeSeriesEntry struct {
+	Date    string `json:"date"`
+	Value   string `json:"value"`
+	Label   string `json:"label"`
+	Year    string `json:"year"`
+	Month   string `json:"month"`
+	Quarter string `json:"quarter"`
+}
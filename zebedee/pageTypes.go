@@ -5,3 +5,15 @@ var HomePage = "home_page"
 
 // TaxonomyLandingPage page type for Taxonomy landing page types.
 var TaxonomyLandingPage = "taxonomy_landing_page"
+
+// ProductPage page type for product pages, the level of the taxonomy beneath taxonomy landing pages.
+var ProductPage = "product_page"
+
+// Bulletin page type for statistical bulletins.
+var Bulletin = "bulletin"
+
+// Article page type for articles.
+var Article = "article"
+
+// DatasetLandingPage page type for the landing pages of datasets.
+var DatasetLandingPage = "dataset_landing_page"
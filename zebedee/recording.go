@@ -0,0 +1,166 @@
+package zebedee
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ONSdigital/go-ns/log"
+)
+
+// recordedRequestHeaders are the request headers saved in fixtures. Other headers, such as credentials, are not saved.
+var recordedRequestHeaders = []string{"X-Request-Id", "Traceparent"}
+
+// Fixture is a Zebedee request and its response, as recorded to a fixture directory.
+type Fixture struct {
+	Endpoint        string            `json:"endpoint"`
+	Params          url.Values        `json:"params"`
+	RequestHeaders  map[string]string `json:"requestHeaders"`
+	Status          int               `json:"status"`
+	ResponseHeaders http.Header       `json:"responseHeaders"`
+	PageType        string            `json:"pageType,omitempty"`
+	Body            string            `json:"body"`
+}
+
+// fixtureName returns the file name of the fixture for a request to the endpoint with the given query parameters.
+func fixtureName(endpoint string, params url.Values) string {
+	hash := sha1.Sum([]byte(endpoint + "?" + params.Encode()))
+	return strings.Trim(strings.Replace(endpoint, "/", "-", -1), "-") + "-" + hex.EncodeToString(hash[:8]) + ".json"
+}
+
+// EnableRecording saves every request sent to Zebedee and its response to a fixture in the directory, from which
+// they can be replayed using NewReplay. Responses served from the cache are not recorded again.
+func (zebedee *Client) EnableRecording(dir string) *Client {
+	basePath := ""
+	if baseURL, err := url.Parse(zebedee.url); err == nil {
+		basePath = strings.TrimSuffix(baseURL.Path, "/")
+	}
+	zebedee.httpClient = &recordingClient{httpClient: zebedee.httpClient, dir: dir, basePath: basePath}
+	return zebedee
+}
+
+// recordingClient sends requests using the wrapped client and saves each request and response as a fixture.
+type recordingClient struct {
+	httpClient
+	dir      string
+	basePath string
+}
+
+func (client *recordingClient) Do(request *http.Request) (*http.Response, error) {
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return response, err
+	}
+
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	fixture := Fixture{
+		Endpoint:        strings.TrimPrefix(request.URL.Path, client.basePath),
+		Params:          request.URL.Query(),
+		RequestHeaders:  make(map[string]string),
+		Status:          response.StatusCode,
+		ResponseHeaders: response.Header,
+		PageType:        response.Header.Get(pageTypeHeader),
+		Body:            string(body),
+	}
+	for _, name := range recordedRequestHeaders {
+		if value := request.Header.Get(name); len(value) > 0 {
+			fixture.RequestHeaders[name] = value
+		}
+	}
+
+	if err := client.save(fixture); err != nil {
+		log.Error(err, log.Data{"description": "failed to record zebedee fixture", "endpoint": fixture.Endpoint})
+	}
+	return response, nil
+}
+
+// save writes the fixture to a temporary file then renames it, so a fixture being replayed is never partially written.
+func (client *recordingClient) save(fixture Fixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(client.dir, ".fixture-")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), filepath.Join(client.dir, fixtureName(fixture.Endpoint, fixture.Params)))
+}
+
+// NewReplay creates a Client that serves the fixtures recorded in the directory rather than calling Zebedee, so a
+// resolve can be reproduced without the Zebedee it was recorded from. Requests without a fixture respond 404.
+func NewReplay(dir string) *Client {
+	return &Client{httpClient: &replayClient{dir: dir}}
+}
+
+// replayClient responds to requests with the recorded fixture for the endpoint and query parameters.
+type replayClient struct {
+	dir string
+}
+
+func (client *replayClient) Get(url string) (*http.Response, error) {
+	request, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(request)
+}
+
+func (client *replayClient) Do(request *http.Request) (*http.Response, error) {
+	if err := request.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	response := &http.Response{
+		Status:     http.StatusText(http.StatusNotFound),
+		StatusCode: http.StatusNotFound,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		Request:    request,
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(client.dir, fixtureName(request.URL.Path, request.URL.Query())))
+	if os.IsNotExist(err) {
+		log.Debug("No zebedee fixture recorded", log.Data{"endpoint": request.URL.Path, "query": request.URL.RawQuery})
+		return response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, err
+	}
+
+	response.Status = http.StatusText(fixture.Status)
+	response.StatusCode = fixture.Status
+	if fixture.ResponseHeaders != nil {
+		response.Header = fixture.ResponseHeaders
+	}
+	response.Body = ioutil.NopCloser(strings.NewReader(fixture.Body))
+	return response, nil
+}
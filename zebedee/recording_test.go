@@ -0,0 +1,60 @@
+package zebedee
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zebedee-fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/zebedee" + dataAPI:
+			w.Header().Set(pageTypeHeader, TaxonomyLandingPage)
+			w.Write([]byte(`{"uri": "/economy", "description": {"title": "Economy"}}`))
+		case "/zebedee" + taxonomyAPI:
+			w.Write([]byte(`[{"uri": "/economy", "type": "taxonomy_landing_page"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	Convey("Should replay the responses recorded from Zebedee.", t, func() {
+		recorder := CreateClient(time.Second, server.URL+"/zebedee").EnableRecording(dir)
+
+		ctx := context.Background()
+		recordedData, recordedType, err := recorder.GetData(ctx, "/economy", "abc123.1")
+		So(err, ShouldBeNil)
+		recordedTaxonomy, err := recorder.GetTaxonomy(ctx, "/", 2, "abc123.2")
+		So(err, ShouldBeNil)
+
+		fixtures, _ := ioutil.ReadDir(dir)
+		So(len(fixtures), ShouldEqual, 2)
+
+		replay := NewReplay(dir)
+		data, pageType, err := replay.GetData(ctx, "/economy", "xyz.1")
+		So(err, ShouldBeNil)
+		So(pageType, ShouldEqual, recordedType)
+		So(data, ShouldResemble, recordedData)
+
+		taxonomy, err := replay.GetTaxonomy(ctx, "/", 2, "xyz.2")
+		So(err, ShouldBeNil)
+		So(taxonomy, ShouldResemble, recordedTaxonomy)
+
+		_, _, err = replay.GetData(ctx, "/business", "xyz.3")
+		So(err.Parameters["actualStatusCode"], ShouldEqual, 404)
+	})
+}
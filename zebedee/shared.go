@@ -0,0 +1,73 @@
+package zebedee
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ONSdigital/go-ns/common"
+)
+
+type sharedKey struct{}
+
+// sharedResponses holds the Zebedee responses for requests made using a context, so that identical requests made
+// concurrently or later with the same context are only sent to Zebedee once.
+type sharedResponses struct {
+	mutex     sync.Mutex
+	responses map[string]*sharedResponse
+}
+
+type sharedResponse struct {
+	done     chan struct{}
+	body     []byte
+	pageType string
+	err      *common.ONSError
+}
+
+// WithSharedResponses returns a context in which identical Zebedee requests share a single response, e.g. so that the
+// pages of a batch resolve share their common upstream calls. Unlike the response cache, failed responses are shared
+// too and nothing is kept once the context is no longer used.
+func WithSharedResponses(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sharedKey{}, &sharedResponses{responses: make(map[string]*sharedResponse)})
+}
+
+// sharedFor returns the shared responses for the context, or nil if responses are not shared.
+func sharedFor(ctx context.Context) *sharedResponses {
+	shared, _ := ctx.Value(sharedKey{}).(*sharedResponses)
+	return shared
+}
+
+// do returns the response shared for the key, calling fetch to get it if it is the first request for the key. Fetch
+// records the call in the timeline of the first request only, so later requests for the key record their wait for the
+// shared response with their own call recorder, which may be nil.
+func (shared *sharedResponses) do(key string, call *callRecorder, fetch func() ([]byte, string, *common.ONSError)) ([]byte, string, *common.ONSError) {
+	shared.mutex.Lock()
+	response, ok := shared.responses[key]
+	if !ok {
+		response = &sharedResponse{done: make(chan struct{})}
+		shared.responses[key] = response
+	}
+	shared.mutex.Unlock()
+
+	if ok {
+		<-response.done
+		call.sharedHit(response.err)
+		call.finish(len(response.body), response.err)
+	} else {
+		response.body, response.pageType, response.err = fetch()
+		close(response.done)
+	}
+	return response.body, response.pageType, copyError(response.err)
+}
+
+// copyError returns a copy of the error so that callers adding parameters to a shared error do not affect each other.
+func copyError(err *common.ONSError) *common.ONSError {
+	if err == nil {
+		return nil
+	}
+
+	copied := &common.ONSError{RootError: err.RootError}
+	for name, value := range err.Parameters {
+		copied.AddParameter(name, value)
+	}
+	return copied
+}
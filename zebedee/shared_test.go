@@ -0,0 +1,79 @@
+package zebedee
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ONSdigital/go-ns/common"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSharedResponses(t *testing.T) {
+
+	Convey("Should fetch identical requests made with the same context once.", t, func() {
+		shared := sharedFor(WithSharedResponses(context.Background()))
+		var fetches int32
+		bodies := make([]string, 10)
+
+		wg := new(sync.WaitGroup)
+		for i := range bodies {
+			wg.Add(1)
+			go func(index int) {
+				defer wg.Done()
+				body, _, _ := shared.do("/taxonomy?uri=%2F", nil, func() ([]byte, string, *common.ONSError) {
+					atomic.AddInt32(&fetches, 1)
+					return []byte("[]"), HomePage, nil
+				})
+				bodies[index] = string(body)
+			}(i)
+		}
+		wg.Wait()
+
+		So(fetches, ShouldEqual, 1)
+		for _, body := range bodies {
+			So(body, ShouldEqual, "[]")
+		}
+	})
+
+	Convey("Should return a copy of shared errors to each caller.", t, func() {
+		shared := sharedFor(WithSharedResponses(context.Background()))
+		fetch := func() ([]byte, string, *common.ONSError) {
+			return nil, "", errorWithReqContextID(errors.New("not found"), incorrectStatusCodeErrDesc, "abc123.1")
+		}
+
+		_, _, first := shared.do("/data?uri=%2F", nil, fetch)
+		first.AddParameter("resolveURI", "/")
+		_, _, second := shared.do("/data?uri=%2F", nil, fetch)
+
+		So(second.RootError, ShouldEqual, first.RootError)
+		So(second.Parameters[requestContextIDParam], ShouldEqual, "abc123.1")
+		So(second.Parameters, ShouldNotContainKey, "resolveURI")
+	})
+
+	Convey("Should record the shared response in the timeline of later requests.", t, func() {
+		ctx := WithSharedResponses(context.Background())
+		shared := sharedFor(ctx)
+		fetch := func() ([]byte, string, *common.ONSError) { return []byte("[]"), "", nil }
+		request := httptest.NewRequest("GET", "/taxonomy?uri=%2F", nil)
+
+		first, firstTimeline := WithTimeline(ctx)
+		shared.do(request.URL.String(), recordCall(first, taxonomyAPI, request), fetch)
+		second, secondTimeline := WithTimeline(ctx)
+		shared.do(request.URL.String(), recordCall(second, taxonomyAPI, request), fetch)
+
+		So(firstTimeline.Calls(), ShouldBeEmpty)
+		calls := secondTimeline.Calls()
+		So(len(calls), ShouldEqual, 1)
+		So(calls[0].Shared, ShouldBeTrue)
+		So(calls[0].Status, ShouldEqual, 200)
+		So(calls[0].Bytes, ShouldEqual, 2)
+	})
+
+	Convey("Should not share responses without a shared context.", t, func() {
+		So(sharedFor(context.Background()), ShouldBeNil)
+	})
+}
@@ -0,0 +1,144 @@
+package zebedee
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/go-ns/common"
+)
+
+// Call describes a single call made to Zebedee, or served from the response cache or the response of an identical
+// call made by another page of a batch, while resolving a request.
+type Call struct {
+	Endpoint         string            `json:"endpoint"`
+	Params           map[string]string `json:"params"`
+	RequestContextID string            `json:"requestContextId"`
+	StartOffsetMs    float64           `json:"startOffsetMs"`
+	DurationMs       float64           `json:"durationMs"`
+	Status           int               `json:"status"`
+	Bytes            int               `json:"bytes"`
+	CacheHit         bool              `json:"cacheHit"`
+	Shared           bool              `json:"shared"`
+	Error            string            `json:"error,omitempty"`
+}
+
+// Timeline records the Zebedee calls made using a context. It is safe for concurrent use.
+type Timeline struct {
+	start time.Time
+	mutex sync.Mutex
+	calls []Call
+}
+
+type timelineKey struct{}
+
+// WithTimeline returns a context that records every Zebedee call made using it in the returned Timeline.
+func WithTimeline(ctx context.Context) (context.Context, *Timeline) {
+	timeline := &Timeline{start: time.Now(), calls: make([]Call, 0)}
+	return context.WithValue(ctx, timelineKey{}, timeline), timeline
+}
+
+// TimelineFrom returns the timeline recording calls for the context, or nil if calls are not being recorded.
+func TimelineFrom(ctx context.Context) *Timeline {
+	timeline, _ := ctx.Value(timelineKey{}).(*Timeline)
+	return timeline
+}
+
+// Calls returns the calls recorded so far, ordered by the time they started.
+func (timeline *Timeline) Calls() []Call {
+	timeline.mutex.Lock()
+	calls := make([]Call, len(timeline.calls))
+	copy(calls, timeline.calls)
+	timeline.mutex.Unlock()
+
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].StartOffsetMs < calls[j].StartOffsetMs })
+	return calls
+}
+
+// Elapsed returns the time since the timeline started.
+func (timeline *Timeline) Elapsed() time.Duration {
+	return time.Since(timeline.start)
+}
+
+func (timeline *Timeline) add(call Call) {
+	timeline.mutex.Lock()
+	timeline.calls = append(timeline.calls, call)
+	timeline.mutex.Unlock()
+}
+
+// callRecorder records a single call in a timeline. A nil callRecorder records nothing, so calls need only be recorded
+// when the request context has a timeline.
+type callRecorder struct {
+	timeline *Timeline
+	start    time.Time
+	call     Call
+}
+
+// recordCall starts recording the call made by the request, returning nil if the context has no timeline.
+func recordCall(ctx context.Context, endpoint string, request *http.Request) *callRecorder {
+	timeline := TimelineFrom(ctx)
+	if timeline == nil {
+		return nil
+	}
+
+	params := make(map[string]string)
+	for name, values := range request.URL.Query() {
+		params[name] = values[0]
+	}
+
+	start := time.Now()
+	return &callRecorder{
+		timeline: timeline,
+		start:    start,
+		call: Call{
+			Endpoint:         endpoint,
+			Params:           params,
+			RequestContextID: request.Header.Get(requests.RequestIDHeaderParam),
+			StartOffsetMs:    milliseconds(start.Sub(timeline.start)),
+		},
+	}
+}
+
+func (recorder *callRecorder) cacheHit() {
+	if recorder != nil {
+		recorder.call.CacheHit = true
+		recorder.call.Status = http.StatusOK
+	}
+}
+
+// sharedHit marks the call as served by the response shared by an identical call.
+func (recorder *callRecorder) sharedHit(err *common.ONSError) {
+	if recorder != nil {
+		recorder.call.Shared = true
+		if err == nil {
+			recorder.call.Status = http.StatusOK
+		}
+	}
+}
+
+func (recorder *callRecorder) status(response *http.Response) {
+	if recorder != nil && response != nil {
+		recorder.call.Status = response.StatusCode
+	}
+}
+
+// finish adds the call to the timeline with the number of bytes returned and the error, if any.
+func (recorder *callRecorder) finish(bytes int, err *common.ONSError) {
+	if recorder == nil {
+		return
+	}
+
+	recorder.call.DurationMs = milliseconds(time.Since(recorder.start))
+	recorder.call.Bytes = bytes
+	if err != nil {
+		recorder.call.Error = err.Error()
+	}
+	recorder.timeline.add(recorder.call)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
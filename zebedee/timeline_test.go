@@ -0,0 +1,62 @@
+package zebedee
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTimeline(t *testing.T) {
+	zebedeeClient := (&Client{httpClient: &testClient{}, url: baseZebedeeURL}).EnableCache(time.Minute)
+	zebedeeClient.setResponseReader(ReadBodyMock)
+	defer zebedeeClient.setResponseReader(ioutil.ReadAll)
+
+	Convey("Should record each Zebedee call made using the context.", t, func() {
+		recorder := httptest.NewRecorder()
+		recorder.WriteHeader(200)
+		responseStub = recorder.Result()
+		errorStub = nil
+		responseBodyReadErrStub = nil
+		responseBodyBytesStub = []byte(`{"uri": "/economy"}`)
+
+		ctx, timeline := WithTimeline(context.Background())
+		zebedeeClient.GetData(ctx, "/economy", "abc123.1")
+		zebedeeClient.GetData(ctx, "/economy", "abc123.2")
+		zebedeeClient.GetParents(context.Background(), "/economy", "xyz.1")
+
+		calls := timeline.Calls()
+		So(len(calls), ShouldEqual, 2)
+
+		So(calls[0].Endpoint, ShouldEqual, dataAPI)
+		So(calls[0].Params, ShouldResemble, map[string]string{uriParam: "/economy"})
+		So(calls[0].RequestContextID, ShouldEqual, "abc123.1")
+		So(calls[0].Status, ShouldEqual, 200)
+		So(calls[0].Bytes, ShouldEqual, len(responseBodyBytesStub))
+		So(calls[0].CacheHit, ShouldBeFalse)
+
+		So(calls[1].RequestContextID, ShouldEqual, "abc123.2")
+		So(calls[1].CacheHit, ShouldBeTrue)
+		So(calls[1].StartOffsetMs, ShouldBeGreaterThanOrEqualTo, calls[0].StartOffsetMs)
+	})
+
+	Convey("Should record the status and error of failed calls.", t, func() {
+		recorder := httptest.NewRecorder()
+		recorder.WriteHeader(500)
+		responseStub = recorder.Result()
+		errorStub = nil
+
+		ctx, timeline := WithTimeline(context.Background())
+		zebedeeClient.GetTimeSeries(ctx, "/economy/cpi", "abc123.3")
+
+		calls := timeline.Calls()
+		So(len(calls), ShouldEqual, 1)
+		So(calls[0].Params, ShouldResemble, map[string]string{uriParam: "/economy/cpi", "series": ""})
+		So(calls[0].Status, ShouldEqual, 500)
+		So(calls[0].Bytes, ShouldEqual, 0)
+		So(calls[0].Error, ShouldNotBeEmpty)
+	})
+}
@@ -1,14 +1,16 @@
 package zebedee
 
 import (
+	"context"
+
 	zebedeeModel "github.com/ONSdigital/dp-content-resolver/zebedee/model"
 	"github.com/ONSdigital/go-ns/common"
 )
 
-// Service defines interface of zebedee service.
+// Service defines interface of zebedee service. Requests are cancelled when the context is done.
 type Service interface {
-	GetData(url string, requestContentID string) (data []byte, pageType string, err *common.ONSError)
-	GetTaxonomy(url string, depth int, requestContentID string) ([]zebedeeModel.ContentNode, *common.ONSError)
-	GetParents(url string, requestContentID string) ([]zebedeeModel.ContentNode, *common.ONSError)
-	GetTimeSeries(url string, requestContentID string) (*zebedeeModel.TimeseriesPage, *common.ONSError)
+	GetData(ctx context.Context, url string, requestContentID string) (data []byte, pageType string, err *common.ONSError)
+	GetTaxonomy(ctx context.Context, url string, depth int, requestContentID string) ([]zebedeeModel.ContentNode, *common.ONSError)
+	GetParents(ctx context.Context, url string, requestContentID string) ([]zebedeeModel.ContentNode, *common.ONSError)
+	GetTimeSeries(ctx context.Context, url string, requestContentID string) (*zebedeeModel.TimeseriesPage, *common.ONSError)
 }
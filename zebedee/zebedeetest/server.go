@@ -0,0 +1,188 @@
+// Package zebedeetest provides a fake Zebedee HTTP server for testing the resolver end to end against the real
+// zebedee.Client.
+package zebedeetest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-content-resolver/requests"
+	"github.com/ONSdigital/dp-content-resolver/zebedee"
+	"github.com/ONSdigital/go-ns/common"
+)
+
+// Endpoints served by the fake Zebedee.
+const (
+	DataEndpoint     = "/data"
+	TaxonomyEndpoint = "/taxonomy"
+	ParentsEndpoint  = "/parents"
+)
+
+const pageTypeHeader = "Ons-Page-Type"
+
+// Behaviour scripts how the fake Zebedee responds to matching requests.
+type Behaviour struct {
+	// Latency delays the response, or until the request is cancelled.
+	Latency time.Duration
+	// Status responds with this status code and an empty body in place of the content, if set.
+	Status int
+	// Drop closes the connection without responding, as a network failure would.
+	Drop bool
+	// Times limits the behaviour to this many matching requests. Zero applies it to every matching request.
+	Times int
+}
+
+// Request is a request received by the fake Zebedee.
+type Request struct {
+	Endpoint         string
+	URI              string
+	Query            string
+	RequestContextID string
+}
+
+// Server is a fake Zebedee serving the data, taxonomy and parents of the content in a Zebedee content directory.
+type Server struct {
+	*httptest.Server
+	content zebedee.Service
+
+	mutex    sync.Mutex
+	scripts  []*script
+	requests []Request
+}
+
+type script struct {
+	endpoint  string
+	uri       string
+	behaviour Behaviour
+	matched   int
+}
+
+// NewServer starts a fake Zebedee serving the content in the given Zebedee content directory. Close the server when
+// it is no longer needed.
+func NewServer(contentDir string) *Server {
+	server := &Server{content: zebedee.NewFileSystem(contentDir)}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	return server
+}
+
+// Script sets the behaviour of requests to the endpoint for the uri. An empty endpoint or uri matches any. The
+// behaviour of the most recent matching script is used.
+func (server *Server) Script(endpoint string, uri string, behaviour Behaviour) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.scripts = append(server.scripts, &script{endpoint: endpoint, uri: uri, behaviour: behaviour})
+}
+
+// Requests returns the requests received so far in the order they were received.
+func (server *Server) Requests() []Request {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]Request(nil), server.requests...)
+}
+
+// Reset removes every script and forgets the requests received.
+func (server *Server) Reset() {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.scripts = nil
+	server.requests = nil
+}
+
+func (server *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	endpoint := req.URL.Path
+	query := req.URL.Query()
+	uri := query.Get("uri")
+	requestContextID := req.Header.Get(requests.RequestIDHeaderParam)
+
+	behaviour := server.receive(Request{Endpoint: endpoint, URI: uri, Query: req.URL.RawQuery, RequestContextID: requestContextID})
+
+	if behaviour.Latency > 0 {
+		select {
+		case <-time.After(behaviour.Latency):
+		case <-req.Context().Done():
+			return
+		}
+	}
+
+	if behaviour.Drop {
+		if hijacker, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hijacker.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	if behaviour.Status != 0 {
+		w.WriteHeader(behaviour.Status)
+		return
+	}
+
+	ctx := context.Background()
+	var body interface{}
+	var err *common.ONSError
+
+	switch endpoint {
+	case DataEndpoint:
+		if _, ok := query["series"]; ok {
+			body, err = server.content.GetTimeSeries(ctx, uri, requestContextID)
+			break
+		}
+
+		var data []byte
+		var pageType string
+		if data, pageType, err = server.content.GetData(ctx, uri, requestContextID); err == nil {
+			w.Header().Set(pageTypeHeader, pageType)
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+			return
+		}
+	case TaxonomyEndpoint:
+		depth, _ := strconv.Atoi(query.Get("depth"))
+		body, err = server.content.GetTaxonomy(ctx, uri, depth, requestContextID)
+	case ParentsEndpoint:
+		body, err = server.content.GetParents(ctx, uri, requestContextID)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		if err.RootError == zebedee.ErrNotFound {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// receive records the request and returns the behaviour scripted for it.
+func (server *Server) receive(request Request) Behaviour {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.requests = append(server.requests, request)
+
+	for i := len(server.scripts) - 1; i >= 0; i-- {
+		s := server.scripts[i]
+		if (len(s.endpoint) > 0 && s.endpoint != request.Endpoint) || (len(s.uri) > 0 && s.uri != request.URI) {
+			continue
+		}
+		if s.behaviour.Times > 0 && s.matched >= s.behaviour.Times {
+			continue
+		}
+		s.matched++
+		return s.behaviour
+	}
+	return Behaviour{}
+}
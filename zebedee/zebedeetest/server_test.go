@@ -0,0 +1,49 @@
+package zebedeetest
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer("../testdata/content")
+	defer server.Close()
+
+	get := func(path string) int {
+		response, err := http.Get(server.URL + path)
+		if err != nil {
+			return 0
+		}
+		response.Body.Close()
+		return response.StatusCode
+	}
+
+	Convey("Should serve the fixtures.", t, func() {
+		So(get("/data?uri=/economy"), ShouldEqual, 200)
+		So(get("/taxonomy?uri=/&depth=2"), ShouldEqual, 200)
+		So(get("/parents?uri=/economy"), ShouldEqual, 200)
+		So(get("/data?uri=/business"), ShouldEqual, 404)
+		So(get("/unknown"), ShouldEqual, 404)
+	})
+
+	Convey("Should apply the most recent matching script a limited number of times.", t, func() {
+		server.Reset()
+		defer server.Reset()
+		server.Script("", "", Behaviour{Status: 500})
+		server.Script(DataEndpoint, "/economy", Behaviour{Status: 503, Times: 1})
+
+		So(get("/data?uri=/economy"), ShouldEqual, 503)
+		So(get("/data?uri=/economy"), ShouldEqual, 500)
+		So(get("/parents?uri=/economy"), ShouldEqual, 500)
+		So(len(server.Requests()), ShouldEqual, 3)
+	})
+
+	Convey("Should drop connections.", t, func() {
+		defer server.Reset()
+		server.Script(ParentsEndpoint, "", Behaviour{Drop: true})
+
+		So(get("/parents?uri=/economy"), ShouldEqual, 0)
+	})
+}